@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/utils"
+	"github.com/mineadmin/mine/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+const (
+	envDBPassword    = "MINE_DB_PASSWORD"
+	envRedisPassword = "MINE_REDIS_PASSWORD"
+)
+
+// defaultAppPort is the port MineAdmin's http server listens on out of the box
+const defaultAppPort = "9501"
+
+// secretSources describes where secrets are read from when not prompted for
+type secretSources struct {
+	dbPasswordFile string
+}
+
+// configOptions holds configuration supplied on the command line. Preset
+// values skip their prompt and are validated exactly like prompted ones.
+type configOptions struct {
+	preset         projectConfig
+	redisDatabases int
+	secrets        secretSources
+	// askFeatures lets the user pick the optional features, when no flag chose them
+	askFeatures bool
+}
+
+// Optional features offered by collectConfiguration
+const (
+	featureSeed     = "Database seeders and super admin account"
+	featureFrontend = "Admin UI in web/"
+	featureDocker   = "Dockerfile and compose file"
+)
+
+// optionalFeatures lists the features in the order they are offered
+var optionalFeatures = []string{featureSeed, featureFrontend, featureDocker}
+
+// addConfigFlags registers the non-interactive configuration flags
+func addConfigFlags(cmd *cobra.Command, opts *configOptions) {
+	cmd.Flags().StringVar(&opts.preset.AppHost, "app-host", "", "Host used in APP_URL")
+	cmd.Flags().StringVar(&opts.preset.AppPort, "app-port", "", "Port the server listens on (default 9501)")
+	cmd.Flags().StringVar(&opts.preset.DBDriver, "db-driver", "", "Database driver (mysql/pgsql)")
+	cmd.Flags().StringVar(&opts.preset.DBHost, "db-host", "", "Database host")
+	cmd.Flags().StringVar(&opts.preset.DBPort, "db-port", "", "Database port")
+	cmd.Flags().StringVar(&opts.preset.DBName, "db-name", "", "Database name")
+	cmd.Flags().StringVar(&opts.preset.DBUser, "db-user", "", "Database username")
+	cmd.Flags().StringVar(&opts.secrets.dbPasswordFile, "db-password-file", "", "Read the database password from a file (or set "+envDBPassword+")")
+	cmd.Flags().StringVar(&opts.preset.RedisHost, "redis-host", "", "Redis host")
+	cmd.Flags().StringVar(&opts.preset.RedisPort, "redis-port", "", "Redis port")
+	cmd.Flags().StringVar(&opts.preset.RedisDB, "redis-db", "", "Redis database number")
+	cmd.Flags().IntVar(&opts.redisDatabases, "redis-databases", 16, "Number of databases configured on the Redis server")
+}
+
+// validate checks every preset value with the same rules used by the prompts
+func (o *configOptions) validate() error {
+	if o.redisDatabases < 1 {
+		return fmt.Errorf("--redis-databases must be at least 1")
+	}
+	for _, f := range o.preset.fields(o.redisDatabases) {
+		if *f.value == "" || f.validate == nil {
+			continue
+		}
+		if err := f.validate(*f.value); err != nil {
+			return fmt.Errorf("invalid %s: %v", strings.ToLower(f.label), err)
+		}
+	}
+	return nil
+}
+
+// projectConfig holds the settings collected for the project's .env file
+type projectConfig struct {
+	AppHost       string
+	AppPort       string
+	DBDriver      string
+	DBHost        string
+	DBPort        string
+	DBName        string
+	DBUser        string
+	DBPassword    string
+	RedisHost     string
+	RedisPort     string
+	RedisPassword string
+	RedisDB       string
+	JwtSecret     string
+	// Features are the optional features picked when configOptions.askFeatures is set
+	Features []string
+}
+
+// configField describes one reviewable setting of projectConfig
+type configField struct {
+	label    string
+	value    *string
+	secret   bool
+	options  []string
+	validate func(string) error
+}
+
+// fields returns the user editable settings in the order they are asked
+func (c *projectConfig) fields(redisDatabases int) []configField {
+	return []configField{
+		{label: "Server host", value: &c.AppHost, validate: validator.Host},
+		{label: "Server port", value: &c.AppPort, validate: validator.PortAvailable},
+		{label: "Database type", value: &c.DBDriver, options: validator.Drivers, validate: validator.Driver},
+		{label: "Database host", value: &c.DBHost, validate: validator.Host},
+		{label: "Database port", value: &c.DBPort, validate: validator.Port},
+		{label: "Database name", value: &c.DBName, validate: c.validateIdentifier},
+		{label: "Database username", value: &c.DBUser, validate: validator.NotEmpty},
+		{label: "Database password", value: &c.DBPassword, secret: true},
+		{label: "Redis host", value: &c.RedisHost, validate: validator.Host},
+		{label: "Redis port", value: &c.RedisPort, validate: validator.Port},
+		{label: "Redis password", value: &c.RedisPassword, secret: true},
+		{label: "Redis database number", value: &c.RedisDB, validate: validator.RedisDB(redisDatabases)},
+	}
+}
+
+// AppURL returns the URL the backend is reachable at
+func (c *projectConfig) AppURL() string {
+	return fmt.Sprintf("http://%s", net.JoinHostPort(c.AppHost, c.AppPort))
+}
+
+// validateIdentifier checks a SQL identifier against the selected driver's rules
+func (c *projectConfig) validateIdentifier(value string) error {
+	return validator.Identifier(c.DBDriver)(value)
+}
+
+// defaultDBPort returns the conventional port of a database driver
+func defaultDBPort(driver string) string {
+	if driver == "pgsql" {
+		return "5432"
+	}
+	return "3306"
+}
+
+// askValue returns an already validated preset, or prompts until validate accepts the input
+func askValue(preset, label, defaultValue string, validate func(string) error) (string, error) {
+	if preset != "" {
+		return preset, nil
+	}
+	return prompt.InputWithValidation(label, defaultValue, validate)
+}
+
+// resolveSecret reads a secret from file, then from the environment, and
+// finally falls back to a masked prompt. The result is always registered
+// for redaction.
+func resolveSecret(file, envKey, label string) (string, error) {
+	var (
+		secret string
+		err    error
+	)
+	if file != "" {
+		secret, err = utils.ReadSecretFile(file)
+	} else if value, ok := os.LookupEnv(envKey); ok {
+		secret = value
+	} else {
+		secret, err = prompt.Password(label)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	prompt.RegisterPassword(secret)
+	return secret, nil
+}
+
+// collectConfiguration asks for the project settings, letting the user review them
+func collectConfiguration(opts configOptions) (*projectConfig, error) {
+	var err error
+	cfg := opts.preset
+
+	prompt.Info("Server Configuration")
+	cfg.AppHost, err = askValue(cfg.AppHost, "Server host", "127.0.0.1", validator.Host)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	cfg.AppPort, err = askValue(cfg.AppPort, "Server port", defaultAppPort, validator.PortAvailable)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+	prompt.Success("Server configuration completed")
+
+	prompt.Info("Database Configuration")
+	if cfg.DBDriver == "" {
+		_, cfg.DBDriver, err = prompt.Select("Database type", validator.Drivers)
+		if err != nil {
+			return nil, fmt.Errorf("database type selection failed: %v", err)
+		}
+	}
+
+	cfg.DBHost, err = askValue(cfg.DBHost, "Database host", "127.0.0.1", validator.Host)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	cfg.DBPort, err = askValue(cfg.DBPort, "Database port", defaultDBPort(cfg.DBDriver), validator.Port)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	// The preset name was validated before the driver was known
+	if cfg.DBName != "" {
+		if err := cfg.validateIdentifier(cfg.DBName); err != nil {
+			return nil, fmt.Errorf("invalid database name: %v", err)
+		}
+	}
+	cfg.DBName, err = askValue(cfg.DBName, "Database name", "mineadmin", cfg.validateIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	cfg.DBUser, err = askValue(cfg.DBUser, "Database username", "root", validator.NotEmpty)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	cfg.DBPassword, err = resolveSecret(opts.secrets.dbPasswordFile, envDBPassword, "Database password")
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+	prompt.Success("Database configuration completed")
+
+	// Redis configuration
+	prompt.Info("Redis Configuration")
+	cfg.RedisHost, err = askValue(cfg.RedisHost, "Redis host", "127.0.0.1", validator.Host)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	cfg.RedisPort, err = askValue(cfg.RedisPort, "Redis port", "6379", validator.Port)
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	cfg.RedisPassword, err = resolveSecret("", envRedisPassword, "Redis password (leave empty if none)")
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %v", err)
+	}
+
+	if cfg.RedisDB == "" {
+		redisDB, err := prompt.InputNumber("Redis database number", 0, 0, opts.redisDatabases-1)
+		if err != nil {
+			return nil, fmt.Errorf("input failed: %v", err)
+		}
+		cfg.RedisDB = strconv.Itoa(redisDB)
+	}
+	prompt.Success("Redis configuration completed")
+
+	if opts.askFeatures {
+		prompt.Info("Optional Features")
+		cfg.Features, err = prompt.MultiSelect("Optional features", optionalFeatures, []string{featureSeed})
+		if err != nil {
+			return nil, fmt.Errorf("feature selection failed: %v", err)
+		}
+	}
+
+	// Generate JWT secret
+	prompt.Info("Generating security configuration")
+	spinner := prompt.StartSpinner("Generating JWT secret...")
+	cfg.JwtSecret, err = utils.GenerateJwtSecret()
+	spinner.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT secret: %v", err)
+	}
+	prompt.RegisterSecret(cfg.JwtSecret)
+	prompt.Success("Security configuration completed")
+
+	// Let the user review and correct everything before touching disk
+	if err := reviewConfiguration(&cfg, opts.redisDatabases); err != nil {
+		return nil, fmt.Errorf("configuration review failed: %v", err)
+	}
+
+	return &cfg, nil
+}
+
+// serverConfigPath returns the Hyperf server configuration of a project
+func serverConfigPath(projectRoot string) string {
+	return filepath.Join(projectRoot, "config", "autoload", "server.php")
+}
+
+// writeServerPort patches the http server port in config/autoload/server.php
+func writeServerPort(projectRoot string, cfg *projectConfig) error {
+	port, err := strconv.Atoi(cfg.AppPort)
+	if err != nil {
+		return fmt.Errorf("invalid server port %q", cfg.AppPort)
+	}
+	if err := utils.SetServerPort(serverConfigPath(projectRoot), port); err != nil {
+		return fmt.Errorf("failed to set server port: %v", err)
+	}
+	return nil
+}
+
+// writeEnvFile writes the .env file into projectRoot
+func writeEnvFile(projectRoot string, cfg *projectConfig) error {
+	// Create .env file
+	prompt.Info("Creating environment configuration file")
+	spinner := prompt.StartSpinner("Writing configuration to .env file...")
+
+	envPath := filepath.Join(projectRoot, ".env")
+	if err := os.WriteFile(envPath, []byte(renderEnv(cfg)), 0644); err != nil {
+		spinner.Stop()
+		return fmt.Errorf("failed to create .env file: %v", err)
+	}
+	spinner.Stop()
+	prompt.Success("Configuration file created successfully")
+	return nil
+}
+
+// reviewConfiguration shows the collected settings and lets the user edit
+// any of them until they confirm
+func reviewConfiguration(cfg *projectConfig, redisDatabases int) error {
+	for {
+		printConfiguration(cfg, redisDatabases)
+
+		ok, err := prompt.Confirm("Write this configuration", true)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		fields := cfg.fields(redisDatabases)
+		labels := make([]string, len(fields))
+		for i, f := range fields {
+			labels[i] = f.label
+		}
+		index, _, err := prompt.Select("Setting to edit", labels)
+		if err != nil {
+			return err
+		}
+		driver := cfg.DBDriver
+		if err := editField(fields[index]); err != nil {
+			return err
+		}
+		if cfg.DBDriver != driver {
+			if err := cfg.driverChanged(driver); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// driverChanged asks again for the settings that depend on the database
+// driver after the review changed it from old: the port, defaulting to the
+// new driver's port when the old default was kept, and a database name the
+// new driver does not accept
+func (c *projectConfig) driverChanged(old string) error {
+	port := c.DBPort
+	if port == defaultDBPort(old) {
+		port = defaultDBPort(c.DBDriver)
+	}
+	var err error
+	if c.DBPort, err = prompt.InputWithValidation("Database port", port, validator.Port); err != nil {
+		return err
+	}
+
+	if err := c.validateIdentifier(c.DBName); err != nil {
+		prompt.Warning(fmt.Sprintf("Invalid database name for %s: %v", c.DBDriver, err))
+		if c.DBName, err = prompt.InputWithValidation("Database name", "mineadmin", c.validateIdentifier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// editField prompts for a new value of a single setting
+func editField(f configField) error {
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case f.options != nil:
+		_, value, err = prompt.Select(f.label, f.options)
+	case f.secret:
+		value, err = prompt.Password(f.label)
+	case f.validate != nil:
+		value, err = prompt.InputWithValidation(f.label, *f.value, f.validate)
+	default:
+		value, err = prompt.Input(f.label, *f.value)
+	}
+	if err != nil {
+		return err
+	}
+
+	*f.value = value
+	return nil
+}
+
+// printConfiguration renders the settings as a table with secrets masked
+func printConfiguration(cfg *projectConfig, redisDatabases int) {
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE")
+	for _, f := range cfg.fields(redisDatabases) {
+		value := *f.value
+		if f.secret && value != "" {
+			value = "******"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", f.label, value)
+	}
+	w.Flush()
+	prompt.Plain("\n📋 Configuration Review\n============================\n" + table.String())
+}
+
+// renderEnv returns the contents of the project's .env file
+func renderEnv(cfg *projectConfig) string {
+	return fmt.Sprintf(`APP_NAME=MineAdmin
+APP_ENV=dev
+APP_DEBUG=false
+
+DB_DRIVER=%s
+DB_HOST=%s
+DB_PORT=%s
+DB_DATABASE=%s
+DB_USERNAME=%s
+DB_PASSWORD=%s
+DB_CHARSET=utf8mb4
+DB_COLLATION=utf8mb4_unicode_ci
+DB_PREFIX=
+
+REDIS_HOST=%s
+REDIS_AUTH=%s
+REDIS_PORT=%s
+REDIS_DB=%s
+
+APP_URL=%s
+
+JWT_SECRET=%s
+
+MINE_ACCESS_TOKEN=(null) # Your MINE_ACCESS_TOKEN
+`,
+		cfg.DBDriver, cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBUser, cfg.DBPassword,
+		cfg.RedisHost, cfg.RedisPassword, cfg.RedisPort, cfg.RedisDB,
+		cfg.AppURL(), cfg.JwtSecret)
+}
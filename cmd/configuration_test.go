@@ -0,0 +1,21 @@
+package cmd
+
+import "testing"
+
+func TestAppURL(t *testing.T) {
+	tests := []struct {
+		host, port string
+		want       string
+	}{
+		{"127.0.0.1", "9501", "http://127.0.0.1:9501"},
+		{"localhost", "9501", "http://localhost:9501"},
+		{"::1", "9501", "http://[::1]:9501"},
+		{"fe80::1", "8080", "http://[fe80::1]:8080"},
+	}
+	for _, tt := range tests {
+		cfg := projectConfig{AppHost: tt.host, AppPort: tt.port}
+		if got := cfg.AppURL(); got != tt.want {
+			t.Errorf("AppURL() with host %q = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
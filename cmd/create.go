@@ -1,41 +1,158 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/mineadmin/mine/internal/config"
 	"github.com/mineadmin/mine/internal/downloader"
+	"github.com/mineadmin/mine/internal/pipeline"
 	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/registry"
 	"github.com/mineadmin/mine/internal/utils"
+	"github.com/mineadmin/mine/internal/validator"
 	"github.com/spf13/cobra"
 )
 
 // NewCreateCmd creates and returns the create command
 func NewCreateCmd() *cobra.Command {
 	var (
-		projectName string
-		language    string
-		version     string
-		platform    string
+		projectName  string
+		language     string
+		version      string
+		platformName string
+		settings     configOptions
+		resumeDir    string
+		skipSteps    []string
+		onlySteps    []string
+		dryRun       bool
+		commands     commandOptions
+		mirror       string
+		mirrorMode   string
+		frontend     frontendOptions
+		seed         seedOptions
+		seedFlag     bool
+		ref          string
+		repo         string
+		tmpl         templateOptions
+		templateVars []string
+		dockerOpts   dockerOptions
 	)
 
 	cmd := &cobra.Command{
 		Use:   "create [projectName]",
 		Short: "Create a new MineAdmin project",
 		Long: `Create a new MineAdmin project with specified language and version.
+The project is set up by a sequence of steps: ` + strings.Join(createStepNames, ", ") + `.
+Completed steps are recorded in .mine/state.json so an interrupted run can be resumed.
 Example:
-  mine create demoProject --language=php --version=v1.0.1 --platform=swow`,
-		Args: cobra.ExactArgs(1),
+  mine create demoProject --language=php --version=v1.0.1 --platform=swow
+  mine create --resume demoProject
+  mine create demoProject --skip-step=migrate
+  mine create demoProject --dry-run
+  mine create demoProject --ref=master --repo=acme/mineadmin
+  mine create demoProject --with-frontend --npm-registry=npmmirror
+  mine create demoProject --template=./acme-template --template-var=company=Acme
+  mine create demoProject --docker-setup`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if resumeDir != "" {
+				return cobra.MaximumNArgs(0)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			projectName = args[0]
+			state := &pipeline.State{}
+			if resumeDir != "" {
+				// Continue a previous run with the options it was started with
+				loaded, err := loadResumeState(resumeDir)
+				if err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+				state = loaded
+				projectName = resumeDir
+				language, version, platformName = state.Language, state.Version, state.Platform
+				ref, repo = state.Ref, state.Repo
+				tmpl.location = state.Template
+				frontend.enabled = frontend.enabled || state.Frontend
+				dockerOpts.enabled = dockerOpts.enabled || state.Docker
+				dockerOpts.setup = dockerOpts.setup || state.DockerSetup
+				prompt.Info(fmt.Sprintf("Resuming project %s", projectName))
+			} else {
+				projectName = args[0]
+			}
+
+			// Reject bad configuration flags before any network access
+			for _, env := range commands.env {
+				if !strings.Contains(env, "=") {
+					prompt.Error(fmt.Sprintf("Invalid --env value %q, expected KEY=VALUE", env))
+					os.Exit(1)
+				}
+			}
+			lang, err := registry.GetLanguage(language)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			if platformName, err = lang.ResolvePlatform(platformName); err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			dockerOpts.enabled = dockerOpts.enabled || dockerOpts.setup
+			if dockerOpts.enabled && language != "php" {
+				prompt.Error("--docker is only supported for php projects")
+				os.Exit(1)
+			}
+			src, err := resolveSource(language, repo)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			if tmpl.vars, err = parseTemplateVars(templateVars); err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			for key, value := range state.TemplateVars {
+				if _, ok := tmpl.vars[key]; !ok {
+					tmpl.vars[key] = value
+				}
+			}
+			if tmpl.location != "" {
+				if tmpl.location, err = resolveTemplateLocation(tmpl.location); err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+			}
+			if err := settings.validate(); err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			if seed.adminEmail != "" {
+				if err := validator.Email(seed.adminEmail); err != nil {
+					prompt.Error(fmt.Sprintf("Invalid --admin-email: %v", err))
+					os.Exit(1)
+				}
+			}
+			composerMirror, err := resolveComposerMirror(cmd, mirror, mirrorMode)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			if frontend.registry != "" {
+				if frontend.registry, err = utils.ResolveNpmRegistry(frontend.registry); err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+			}
 
-			// For PHP projects, handle version selection if not specified
-			if language == "php" && version == "latest" {
+			// Let the user pick a release when neither a version nor a ref was given
+			if version == "latest" && ref == "" {
 				prompt.Info("Fetching available MineAdmin versions...")
-				versions, err := downloader.NewDownloader(language, "", platform).ListVersions()
+				versions, err := downloader.NewDownloader(language, "", src).ListVersions(cmd.Context())
 				if err != nil {
 					prompt.Error(fmt.Sprintf("Failed to get versions: %v", err))
 					os.Exit(1)
@@ -49,293 +166,188 @@ Example:
 				}
 				version = selectedVersion
 			}
-
-			dl := downloader.NewDownloader(language, version, platform)
-			prompt.Info(fmt.Sprintf("Creating project %s", projectName))
-			prompt.Info(fmt.Sprintf("Language: %s, Version: %s, Platform: %s", language, version, platform))
-
-			// Start a spinner for the download process
-			spinner := prompt.StartSpinner("Downloading and extracting project files...")
-			err := dl.Download(projectName)
-			spinner.Stop()
-
-			if err != nil {
-				prompt.Error(fmt.Sprintf("Failed to create project: %v", err))
-				os.Exit(1)
+			state.Language, state.Version, state.Platform = language, version, platformName
+			state.Ref, state.Repo = ref, repo
+			state.Template = tmpl.location
+			state.Frontend = frontend.enabled
+			state.Docker, state.DockerSetup = dockerOpts.enabled, dockerOpts.setup
+			if state.ProjectRoot == "" {
+				state.ProjectRoot = projectRootOf(projectName)
 			}
 
-			// For PHP projects, handle swow platform specific operations
-			if language == "php" && platform == "swow" {
-				// Check if version > 3.0
-				if utils.CompareVersions(version, "3.0") > 0 {
-					projectRoot := projectName
-					if strings.Contains(projectName, "mineadmin-") {
-						projectRoot = filepath.Dir(projectName)
-					}
-
-					// Replace files for swow platform
-					spinner = prompt.StartSpinner("Configuring project for Swow platform...")
-
-					// Get files from GitHub and replace
-					files := []struct {
-						srcPath string
-						dstPath string
-					}{
-						{
-							srcPath: ".github/ci/hyperf.php",
-							dstPath: filepath.Join(projectRoot, "bin", "hyperf.php"),
-						},
-						{
-							srcPath: ".github/ci/server.php",
-							dstPath: filepath.Join(projectRoot, "config", "autoload", "server.php"),
-						},
-						{
-							srcPath: ".github/ci/bootstrap.php",
-							dstPath: filepath.Join(projectRoot, "tests", "bootstrap.php"),
-						},
-					}
-
-					for _, file := range files {
-						content, err := utils.GetGitHubFileContent("mineadmin/MineAdmin", version, file.srcPath)
-						if err != nil {
-							spinner.Stop()
-							prompt.Error(fmt.Sprintf("Failed to fetch %s from GitHub: %v", file.srcPath, err))
-							os.Exit(1)
-						}
-
-						// Ensure target directory exists
-						if err := os.MkdirAll(filepath.Dir(file.dstPath), 0755); err != nil {
-							spinner.Stop()
-							prompt.Error(fmt.Sprintf("Failed to create directory for %s: %v", file.dstPath, err))
-							os.Exit(1)
-						}
-
-						if err := ioutil.WriteFile(file.dstPath, content, 0644); err != nil {
-							spinner.Stop()
-							prompt.Error(fmt.Sprintf("Failed to write %s: %v", file.dstPath, err))
-							os.Exit(1)
-						}
-					}
-
-					// Modify composer.json
-					composerPath := filepath.Join(projectRoot, "composer.json")
-					if err := utils.ModifyComposerJSON(composerPath); err != nil {
-						spinner.Stop()
-						prompt.Error(fmt.Sprintf("Failed to modify composer.json: %v", err))
-						os.Exit(1)
-					}
-
-					spinner.Stop()
-					prompt.Success("Project configured for Swow platform")
-				}
+			// Offer the optional features when no flag picked them and someone answers the prompts
+			_, piped := prompt.Current().(*prompt.LinePrompter)
+			featureFlags := cmd.Flags().Changed("seed") || cmd.Flags().Changed("with-frontend") ||
+				cmd.Flags().Changed("docker") || cmd.Flags().Changed("docker-setup")
+			settings.askFeatures = resumeDir == "" && language == "php" && !featureFlags && !piped
+
+			binPhp, _ := cmd.Flags().GetString("bin-php")
+			binComposer, _ := cmd.Flags().GetString("bin-composer")
+			c := &createContext{
+				projectName: projectName,
+				projectRoot: state.ProjectRoot,
+				language:    language,
+				version:     version,
+				platform:    platformName,
+				binPhp:      binPhp,
+				binComposer: binComposer,
+				config:      settings,
+				commands:    commands,
+				mirror:      composerMirror,
+				frontend:    frontend,
+				seed:        seed,
+				template:    tmpl,
+				docker:      dockerOpts,
+				state:       state,
+				source:      src,
+				ref:         ref,
+			}
+			if cmd.Flags().Changed("seed") {
+				c.seed.seed = &seedFlag
 			}
 
-			// For PHP projects, collect configuration first
-			if language == "php" {
-				collectConfiguration(projectName)
-
-				// Then check environment and run setup
-				binPhp, _ := cmd.Flags().GetString("bin-php")
-				binComposer, _ := cmd.Flags().GetString("bin-composer")
-
-				// Check if PHP and Composer commands exist
-				if !utils.CheckCommandExists(binPhp) {
-					prompt.Warning(fmt.Sprintf("PHP command '%s' not found - skipping composer install and migrations", binPhp))
-					prompt.Error("Please install PHP and run the following commands manually:")
-					prompt.Error(fmt.Sprintf("1. %s install", binComposer))
-					prompt.Error(fmt.Sprintf("2. %s bin/hyperf.php migrate", binPhp))
-					return
-				}
-				if !utils.CheckCommandExists(binComposer) {
-					prompt.Warning(fmt.Sprintf("Composer command '%s' not found - skipping composer install and migrations", binComposer))
-					prompt.Error("Please install Composer and run the following commands manually:")
-					prompt.Error(fmt.Sprintf("1. %s install", binComposer))
-					prompt.Error(fmt.Sprintf("2. %s bin/hyperf.php migrate", binPhp))
-					return
-				}
+			if dryRun {
+				prompt.Info(fmt.Sprintf("Dry run: nothing will be written to %s", projectName))
+			} else if resumeDir == "" {
+				prompt.Info(fmt.Sprintf("Creating project %s", projectName))
+				prompt.Info(fmt.Sprintf("Language: %s, Version: %s, Platform: %s", language, version, platformName))
+			}
 
-				// Check platform extension
-				spinner := prompt.StartSpinner(fmt.Sprintf("Checking %s extension...", platform))
-				extExists, err := utils.CheckPhpExtension(binPhp, platform)
-				spinner.Stop()
-				if err != nil {
-					prompt.Warning(fmt.Sprintf("Failed to check %s extension: %v", platform, err))
-					prompt.Info("Project downloaded but may not run without the extension")
-					return
-				}
-				if !extExists {
-					prompt.Warning(fmt.Sprintf("%s extension is not installed", platform))
-					prompt.Info(fmt.Sprintf("Project downloaded but will not run without %s extension:", platform))
-					if platform == "swow" {
-						prompt.Info("Swow extension: https://github.com/swow/swow")
-					} else {
-						prompt.Info("Swoole extension: https://github.com/swoole/swoole-src")
-					}
-					return
+			p := pipeline.New(c.projectRoot, state, c.steps()...)
+			err = p.Run(cmd.Context(), pipeline.Options{
+				Resume: resumeDir != "",
+				Skip:   skipSteps,
+				Only:   onlySteps,
+				DryRun: dryRun,
+				Known:  createStepNames,
+			})
+			c.closeTemplate()
+			if err != nil {
+				var stepErr *pipeline.StepError
+				if !errors.As(err, &stepErr) {
+					prompt.Error(err.Error())
+					os.Exit(1)
 				}
 
-				// Run composer install
-				projectRoot := projectName
-				if strings.Contains(projectName, "mineadmin-") {
-					projectRoot = filepath.Dir(projectName)
+				canceled := cmd.Context().Err() != nil
+				if canceled {
+					prompt.Warning(fmt.Sprintf("Cancelled during step %s", stepErr.Step))
+				} else {
+					prompt.Error(fmt.Sprintf("Failed to create project: %v", stepErr.Err))
 				}
-
-				prompt.Info("Running composer install...")
-				err = utils.RunCommandWithOutput(binComposer, []string{"install"}, projectRoot)
-				if err != nil {
-					prompt.Warning(fmt.Sprintf("Composer install failed: %v", err))
-					prompt.Info("Project downloaded but dependencies not installed")
-					return
+				if stepErr.Step != stepDownload && !dryRun {
+					prompt.Info(fmt.Sprintf("Fix the problem and continue with: mine create --resume %s", c.projectRoot))
 				}
-
-				// Run hyperf.php migrate
-				prompt.Info("Running database migrations...")
-				hyperfPath := filepath.Join(projectRoot, "bin", "hyperf.php")
-				err = utils.RunCommandWithOutput(binPhp, []string{hyperfPath, "migrate"}, projectRoot)
-				if err != nil {
-					prompt.Warning(fmt.Sprintf("Database migration failed: %v", err))
-					prompt.Info("Project downloaded but database not migrated")
-					return
+				if canceled {
+					os.Exit(exitCanceled)
 				}
+				os.Exit(1)
 			}
 
+			if dryRun {
+				prompt.Success("Dry run completed, no changes were made")
+				return
+			}
 			prompt.Success(fmt.Sprintf("Successfully created project %s", projectName))
 		},
 	}
 
-	cmd.Flags().StringVarP(&language, "language", "l", "php", "Programming language (php/go/js)")
+	cmd.Flags().StringVarP(&language, "language", "l", "php", "Programming language ("+strings.Join(registry.LanguageNames(), "/")+")")
 	cmd.Flags().StringVarP(&version, "version", "v", "latest", "Version of MineAdmin")
-	cmd.Flags().StringVarP(&platform, "platform", "p", "swow", "Platform (swow/swoole)")
+	cmd.Flags().StringVarP(&platformName, "platform", "p", "", "Platform ("+registry.PlatformSummary()+"), defaults to the first one listed")
+	cmd.Flags().StringVar(&ref, "ref", "", "Download a branch or commit instead of a release (--version then only selects the platform overlay)")
+	cmd.Flags().StringVar(&repo, "repo", "", "Download from another repository, as owner/name, such as a private fork")
+	cmd.Flags().StringVar(&tmpl.location, "template", "", "Layer a template (directory, git URL or zip) over the project, see template.yaml in the README")
+	cmd.Flags().StringArrayVar(&templateVars, "template-var", nil, "Answer a template prompt as KEY=VALUE (repeatable)")
+	cmd.Flags().StringVar(&resumeDir, "resume", "", "Resume an interrupted create in the given project directory")
+	cmd.Flags().StringSliceVar(&skipSteps, "skip-step", nil, "Steps to skip ("+strings.Join(createStepNames, ", ")+")")
+	cmd.Flags().StringSliceVar(&onlySteps, "only-step", nil, "Only run the given steps")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print what would be done without touching disk or running commands")
+	cmd.Flags().DurationVar(&commands.timeout, "command-timeout", 30*time.Minute, "Timeout for each setup command such as composer install (0 for none)")
+	cmd.Flags().StringArrayVar(&commands.env, "env", nil, "Extra KEY=VALUE environment for setup commands (repeatable)")
+	cmd.Flags().StringVar(&mirror, "composer-mirror", "", "Composer mirror for composer install (aliyun/tencent/huawei or a URL)")
+	cmd.Flags().StringVar(&mirrorMode, "composer-mirror-mode", utils.MirrorTemporary, "Use the mirror for this install only (temporary) or save it to composer.json (permanent)")
+	cmd.Flags().BoolVar(&frontend.enabled, "with-frontend", false, "Also install the admin UI in web/ and point it at the backend")
+	cmd.Flags().StringVar(&frontend.registry, "npm-registry", "", "npm registry mirror for the frontend install (npmmirror/tencent/huawei or a URL)")
+	cmd.Flags().StringVar(&frontend.binNode, "bin-node", "node", "Node binary path")
+	cmd.Flags().BoolVar(&seedFlag, "seed", false, "Run database seeders and set up the super admin (asked when not given)")
+	cmd.Flags().StringVar(&seed.adminUsername, "admin-username", "", "Super admin username")
+	cmd.Flags().StringVar(&seed.adminEmail, "admin-email", "", "Super admin email")
+	cmd.Flags().StringVar(&seed.adminPasswordFile, "admin-password-file", "", "Read the super admin password from a file (or set "+envAdminPassword+")")
+	cmd.Flags().StringVar(&seed.adminTable, "admin-table", "user", "Table holding the super admin account, without DB_PREFIX")
+	cmd.Flags().BoolVar(&dockerOpts.enabled, "docker", false, "Generate a Dockerfile and compose file for the platform")
+	cmd.Flags().BoolVar(&dockerOpts.setup, "docker-setup", false, "Run composer install, migrations and seeders inside the app container (implies --docker)")
+	addDockerFlags(cmd, &dockerOpts)
+	cmd.MarkFlagsMutuallyExclusive("skip-step", "only-step")
+	addConfigFlags(cmd, &settings)
+	cmd.RegisterFlagCompletionFunc("language", completeLanguages)
+	cmd.RegisterFlagCompletionFunc("platform", completePlatforms)
 
 	return cmd
 }
 
-func collectConfiguration(projectDir string) {
-	prompt.Info("Database Configuration")
-	spinner := prompt.StartSpinner("Preparing database configuration...")
-	_, dbType, err := prompt.Select("Database type", []string{"mysql", "pgsql"})
-	spinner.Stop()
+// resolveComposerMirror picks the composer mirror from flags, falling back to
+// the user configuration for anything not given on the command line
+func resolveComposerMirror(cmd *cobra.Command, mirror, mode string) (composerMirror, error) {
+	cfg, err := config.Load()
 	if err != nil {
-		prompt.Error(fmt.Sprintf("Database type selection failed: %v", err))
-		os.Exit(1)
+		return composerMirror{}, err
 	}
-
-	dbHost, err := prompt.Input("Database host", "127.0.0.1")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+	if !cmd.Flags().Changed("composer-mirror") {
+		mirror = cfg.ComposerMirror
 	}
-
-	dbPort, err := prompt.Input("Database port", "3306")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+	if !cmd.Flags().Changed("composer-mirror-mode") && cfg.ComposerMirrorMode != "" {
+		mode = cfg.ComposerMirrorMode
 	}
-
-	dbName, err := prompt.Input("Database name", "mineadmin")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+	if mirror == "" {
+		return composerMirror{}, nil
 	}
 
-	dbUser, err := prompt.Input("Database username", "root")
+	mirrorURL, err := utils.ResolveComposerMirror(mirror)
 	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+		return composerMirror{}, err
 	}
-
-	dbPass, err := prompt.Input("Database password", "root")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+	if err := utils.ValidateMirrorMode(mode); err != nil {
+		return composerMirror{}, err
 	}
-	prompt.Success("Database configuration completed")
+	return composerMirror{url: mirrorURL, mode: mode}, nil
+}
 
-	// Redis configuration
-	prompt.Info("Redis Configuration")
-	spinner = prompt.StartSpinner("Preparing Redis configuration...")
-	redisHost, err := prompt.Input("Redis host", "127.0.0.1")
-	spinner.Stop()
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+// projectRootOf returns the directory the project files are extracted to
+func projectRootOf(projectName string) string {
+	if strings.Contains(projectName, "mineadmin-") {
+		return filepath.Dir(projectName)
 	}
+	return projectName
+}
 
-	redisPort, err := prompt.Input("Redis port", "6379")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+// loadResumeState reads the state of a run started as "mine create <projectName>".
+// The state lives in the project root, which is not projectName itself for
+// names containing "mineadmin-", so that root is tried as well.
+func loadResumeState(projectName string) (*pipeline.State, error) {
+	state, err := pipeline.LoadState(projectName)
+	if err == nil {
+		return state, nil
 	}
-
-	redisPass, err := prompt.Input("Redis password (leave empty if none)", "")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
+	if root := projectRootOf(projectName); root != projectName {
+		if state, rootErr := pipeline.LoadState(root); rootErr == nil && state.ProjectRoot == root {
+			return state, nil
+		}
 	}
+	return nil, err
+}
 
-	redisDB, err := prompt.Input("Redis database number", "0")
-	if err != nil {
-		prompt.Error(fmt.Sprintf("Input failed: %v", err))
-		os.Exit(1)
-	}
-	prompt.Success("Redis configuration completed")
+// completeLanguages completes --language with the supported languages
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return registry.LanguageNames(), cobra.ShellCompDirectiveNoFileComp
+}
 
-	// Generate JWT secret
-	prompt.Info("Generating security configuration")
-	spinner = prompt.StartSpinner("Generating JWT secret...")
-	jwtSecret, err := utils.GenerateJwtSecret()
-	spinner.Stop()
+// completePlatforms completes --platform with the platforms of the selected --language
+func completePlatforms(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	name, _ := cmd.Flags().GetString("language")
+	lang, err := registry.GetLanguage(name)
 	if err != nil {
-		prompt.Error(fmt.Sprintf("Failed to generate JWT secret: %v", err))
-		os.Exit(1)
-	}
-	prompt.Success("Security configuration completed")
-
-	// Create .env file
-	prompt.Info("Creating environment configuration file")
-	spinner = prompt.StartSpinner("Writing configuration to .env file...")
-	envContent := fmt.Sprintf(`APP_NAME=MineAdmin
-APP_ENV=dev
-APP_DEBUG=false
-
-DB_DRIVER=%s
-DB_HOST=%s
-DB_PORT=%s
-DB_DATABASE=%s
-DB_USERNAME=%s
-DB_PASSWORD=%s
-DB_CHARSET=utf8mb4
-DB_COLLATION=utf8mb4_unicode_ci
-DB_PREFIX=
-
-REDIS_HOST=%s
-REDIS_AUTH=%s
-REDIS_PORT=%s
-REDIS_DB=%s
-
-APP_URL=http://127.0.0.1:9501
-
-JWT_SECRET=%s
-
-MINE_ACCESS_TOKEN=(null) # Your MINE_ACCESS_TOKEN
-`,
-		dbType, dbHost, dbPort, dbName, dbUser, dbPass,
-		redisHost, redisPass, redisPort, redisDB,
-		jwtSecret)
-
-	// Ensure .env is created in the project root (not in mineadmin-version subdirectory)
-	envPath := filepath.Join(projectDir, ".env")
-
-	// If we're in a subdirectory (like mineadmin-version), find the project root
-	if strings.Contains(projectDir, "mineadmin-") {
-		rootDir := filepath.Dir(projectDir)
-		envPath = filepath.Join(rootDir, ".env")
-	}
-	if err := os.WriteFile(envPath, []byte(envContent), 0644); err != nil {
-		spinner.Stop()
-		prompt.Error(fmt.Sprintf("Failed to create .env file: %v", err))
-		os.Exit(1)
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
-	spinner.Stop()
-	prompt.Success("Configuration file created successfully")
+	return lang.Platforms, cobra.ShellCompDirectiveNoFileComp
 }
@@ -0,0 +1,578 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mineadmin/mine/internal/composer"
+	"github.com/mineadmin/mine/internal/downloader"
+	"github.com/mineadmin/mine/internal/pipeline"
+	"github.com/mineadmin/mine/internal/platform"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/source"
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// Names of the create steps, in the order they run
+const (
+	stepDownload  = "download"
+	stepPlatform  = "platform"
+	stepConfigure = "configure"
+	stepCheckEnv  = "check-env"
+	stepComposer  = "composer-install"
+	stepMigrate   = "migrate"
+	stepSeed      = "seed"
+	stepAdmin     = "admin"
+	stepFrontend  = "frontend"
+	stepDeps      = "dependencies"
+	stepTemplate  = "template"
+	stepDocker    = "docker"
+	stepPostSteps = "template-post"
+)
+
+var createStepNames = []string{stepDownload, stepPlatform, stepConfigure, stepTemplate, stepDocker, stepCheckEnv, stepComposer, stepMigrate, stepSeed, stepAdmin, stepFrontend, stepDeps, stepPostSteps}
+
+// createContext carries the options shared by the create steps
+type createContext struct {
+	projectName string
+	projectRoot string
+	language    string
+	version     string
+	platform    string
+	binPhp      string
+	binComposer string
+	config      configOptions
+	commands    commandOptions
+	mirror      composerMirror
+	frontend    frontendOptions
+	seed        seedOptions
+	template    templateOptions
+	docker      dockerOptions
+	state       *pipeline.State
+	source      source.Source
+	// ref is the branch or commit to download instead of the version's release
+	ref string
+}
+
+// fetchRef returns the ref project files are downloaded at
+func (c *createContext) fetchRef() string {
+	if c.ref != "" {
+		return c.ref
+	}
+	return c.version
+}
+
+// composerMirror is the repository composer install uses instead of packagist.org
+type composerMirror struct {
+	url  string
+	mode string
+}
+
+// Temporary composer files used to install through a mirror without editing composer.json
+const (
+	mirrorComposerFile = "composer.mirror.json"
+	mirrorLockFile     = "composer.mirror.lock"
+)
+
+// commandOptions controls the subprocesses run by create
+type commandOptions struct {
+	timeout time.Duration
+	env     []string
+}
+
+// command builds a subprocess run in the project root with the configured
+// timeout and environment on top of env
+func (c *createContext) command(name string, args []string, env ...string) utils.Command {
+	return utils.Command{
+		Name:    name,
+		Args:    args,
+		Dir:     c.projectRoot,
+		Env:     append(env, c.commands.env...),
+		Timeout: c.commands.timeout,
+	}
+}
+
+// setupCommand builds a PHP or Composer command of the project setup. With
+// --docker-setup it runs in the app container, where bin is on the PATH.
+func (c *createContext) setupCommand(hostBin, bin string, args []string, env ...string) utils.Command {
+	if c.docker.setup {
+		return composeRun(c.command(bin, args, env...))
+	}
+	return c.command(hostBin, args, env...)
+}
+
+// composerEnv returns the environment composer runs with. The memory limit is
+// lifted for large dependency trees and running as root is allowed without a prompt.
+func composerEnv() []string {
+	env := []string{"COMPOSER_MEMORY_LIMIT=-1"}
+	if os.Geteuid() == 0 {
+		env = append(env, "COMPOSER_ALLOW_SUPERUSER=1")
+	}
+	return env
+}
+
+// editComposerJSON applies edit to the project's composer.json, keeping its
+// formatting, and returns what changed
+func editComposerJSON(projectRoot string, edit func(f *composer.File) error) ([]composer.Change, error) {
+	f, err := composer.Load(filepath.Join(projectRoot, "composer.json"))
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(f); err != nil {
+		return nil, err
+	}
+	return f.Changes(), f.Save()
+}
+
+// setComposerMirror points the composer file at path to the mirror
+func setComposerMirror(path, mirrorURL string) error {
+	f, err := composer.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := f.SetMirror(mirrorURL); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// steps returns the create pipeline for the project's language
+func (c *createContext) steps() []pipeline.Step {
+	steps := []pipeline.Step{
+		{Name: stepDownload, Description: "Download and extract project files", Run: c.download, Plan: c.planDownload},
+		{Name: stepPlatform, Description: "Configure project for the runtime platform", Run: c.configurePlatform, Plan: c.planPlatform},
+	}
+	if c.language != "php" {
+		steps = append(steps, c.templateSteps()...)
+		steps = append(steps, pipeline.Step{Name: stepDeps, Description: "Install project dependencies", Run: c.installDependencies, Plan: c.planInstallDependencies})
+		return append(steps, c.postSteps()...)
+	}
+
+	steps = append(steps, pipeline.Step{Name: stepConfigure, Description: "Collect configuration and write .env", Run: c.configure, Plan: c.planConfigure})
+	steps = append(steps, c.templateSteps()...)
+	if c.docker.enabled || c.config.askFeatures {
+		steps = append(steps, c.optionalStep(pipeline.Step{Name: stepDocker, Description: "Generate Dockerfile and compose file", Run: c.generateDocker, Plan: c.planDocker}, &c.docker.enabled))
+	}
+	steps = append(steps,
+		pipeline.Step{Name: stepCheckEnv, Description: "Check PHP, Composer and platform extension", Run: c.checkEnvironment, Plan: c.planCheckEnvironment},
+		pipeline.Step{Name: stepComposer, Description: "Install Composer dependencies", Run: c.composerInstall, Plan: c.planComposerInstall},
+		pipeline.Step{Name: stepMigrate, Description: "Run database migrations", Run: c.migrate, Plan: c.planMigrate},
+		pipeline.Step{Name: stepSeed, Description: "Run database seeders (optional)", Run: c.seedDatabase, Plan: c.planSeed},
+		pipeline.Step{Name: stepAdmin, Description: "Set up the super admin account", Run: c.setupAdmin, Plan: c.planAdmin},
+	)
+	if c.frontend.enabled || c.config.askFeatures {
+		steps = append(steps, c.optionalStep(pipeline.Step{Name: stepFrontend, Description: "Install the admin UI and point it at the backend", Run: c.setupFrontend, Plan: c.planFrontend}, &c.frontend.enabled))
+	}
+	return append(steps, c.postSteps()...)
+}
+
+// optionalStep wraps the step of a feature that may still be turned off when
+// the configure step asks for the optional features
+func (c *createContext) optionalStep(step pipeline.Step, enabled *bool) pipeline.Step {
+	run, plan := step.Run, step.Plan
+	step.Run = func(ctx context.Context) error {
+		if !*enabled {
+			prompt.Info(fmt.Sprintf("Skipping %s, not selected", step.Name))
+			return nil
+		}
+		return run(ctx)
+	}
+	step.Plan = func(ctx context.Context) error {
+		if !*enabled {
+			printPlan("skip, not selected")
+			return nil
+		}
+		return plan(ctx)
+	}
+	return step
+}
+
+// applyFeatures enables the optional features picked during configuration
+// and records them for resumed runs
+func (c *createContext) applyFeatures(cfg *projectConfig) {
+	if !c.config.askFeatures {
+		return
+	}
+	seed := slices.Contains(cfg.Features, featureSeed)
+	c.seed.seed = &seed
+	c.state.Seed = &seed
+	c.frontend.enabled = slices.Contains(cfg.Features, featureFrontend)
+	c.docker.enabled = slices.Contains(cfg.Features, featureDocker)
+	c.state.Frontend, c.state.Docker = c.frontend.enabled, c.docker.enabled
+}
+
+// templateSteps returns the step applying the selected template, if any.
+// It runs after configure so templates can use the collected settings.
+func (c *createContext) templateSteps() []pipeline.Step {
+	if c.template.location == "" {
+		return nil
+	}
+	return []pipeline.Step{{Name: stepTemplate, Description: "Apply the project template", Run: c.applyTemplate, Plan: c.planTemplate}}
+}
+
+// postSteps returns the step running the template's post steps, if any
+func (c *createContext) postSteps() []pipeline.Step {
+	if c.template.location == "" {
+		return nil
+	}
+	return []pipeline.Step{{Name: stepPostSteps, Description: "Run the template's post steps", Run: c.runTemplatePostSteps, Plan: c.planTemplatePostSteps}}
+}
+
+func (c *createContext) download(ctx context.Context) error {
+	dl := downloader.NewDownloader(c.language, c.fetchRef(), c.source)
+
+	// Start a spinner for the download process
+	spinner := prompt.StartSpinner("Downloading and extracting project files...")
+	if err := dl.Download(ctx, c.projectName); err != nil {
+		spinner.Fail()
+		return err
+	}
+	spinner.Done()
+	return nil
+}
+
+// platformOverlay returns the overlay of the selected platform profile for
+// the project's version, or nil when the downloaded project needs none
+func (c *createContext) platformOverlay() *platform.Overlay {
+	profile, ok := platform.Lookup(c.platform)
+	if c.language != "php" || !ok || profile.Upstream {
+		return nil
+	}
+	return profile.OverlayFor(c.version)
+}
+
+// configurePlatform applies the platform profile's overlay to the project
+func (c *createContext) configurePlatform(ctx context.Context) error {
+	overlay := c.platformOverlay()
+	if overlay == nil {
+		return nil
+	}
+
+	spinner := prompt.StartSpinner(fmt.Sprintf("Configuring project for %s platform...", c.platform))
+	if _, err := applyOverlay(ctx, c.source, c.projectRoot, c.fetchRef(), overlay, ""); err != nil {
+		spinner.Fail()
+		return err
+	}
+	spinner.Stop()
+	prompt.Success(fmt.Sprintf("Project configured for %s platform", c.platform))
+	return nil
+}
+
+func (c *createContext) configure(ctx context.Context) error {
+	cfg, err := collectConfiguration(c.config)
+	if err != nil {
+		return err
+	}
+	c.applyFeatures(cfg)
+	// Runs after the platform step so the port also lands in the overlay's server.php
+	if err := writeServerPort(c.projectRoot, cfg); err != nil {
+		return err
+	}
+	return writeEnvFile(c.projectRoot, cfg)
+}
+
+// checkEnvironment verifies PHP, Composer and the platform requirements
+func (c *createContext) checkEnvironment(ctx context.Context) error {
+	// The image provides PHP, Composer and the extension
+	if c.docker.setup {
+		return checkDocker(ctx)
+	}
+
+	// Check if PHP and Composer commands exist
+	if !utils.CheckCommandExists(c.binPhp) {
+		prompt.Warning(fmt.Sprintf("PHP command '%s' not found", c.binPhp))
+		c.printManualSteps()
+		return fmt.Errorf("PHP command '%s' not found", c.binPhp)
+	}
+	if !utils.CheckCommandExists(c.binComposer) {
+		prompt.Warning(fmt.Sprintf("Composer command '%s' not found", c.binComposer))
+		c.printManualSteps()
+		return fmt.Errorf("Composer command '%s' not found", c.binComposer)
+	}
+
+	profile, err := platform.Get(c.platform)
+	if err != nil {
+		return err
+	}
+	if err := checkPlatformRequirements(ctx, c.binPhp, profile); err != nil {
+		prompt.Info(fmt.Sprintf("Project downloaded but will not run on %s until this is fixed", c.platform))
+		return err
+	}
+	return nil
+}
+
+// printManualSteps explains how to finish the setup by hand
+func (c *createContext) printManualSteps() {
+	prompt.Info("Install the missing tool and resume, or run the following commands manually:")
+	prompt.Info(fmt.Sprintf("1. %s install", c.binComposer))
+	prompt.Info(fmt.Sprintf("2. %s bin/hyperf.php migrate", c.binPhp))
+}
+
+func (c *createContext) composerInstall(ctx context.Context) error {
+	cmd := c.composerInstallCommand()
+	temporaryMirror := false
+	if c.mirror.url != "" {
+		if c.mirror.mode == utils.MirrorPermanent {
+			_, err := editComposerJSON(c.projectRoot, func(f *composer.File) error {
+				return f.SetMirror(c.mirror.url)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to configure composer mirror: %v", err)
+			}
+			prompt.Info(fmt.Sprintf("Using composer mirror %s (saved to composer.json)", c.mirror.url))
+		} else {
+			cleanup, err := c.prepareTemporaryMirror(ctx)
+			defer cleanup()
+			if err != nil {
+				prompt.Warning(fmt.Sprintf("Installing without composer mirror %s: %v", c.mirror.url, err))
+			} else {
+				temporaryMirror = true
+				cmd.Env = append(cmd.Env, "COMPOSER="+mirrorComposerFile)
+				prompt.Info(fmt.Sprintf("Using composer mirror %s for this install only", c.mirror.url))
+			}
+		}
+	}
+
+	hadLock := c.hasComposerLock()
+	prompt.Info("Running composer install...")
+	if err := utils.RunCommand(ctx, cmd); err != nil {
+		return fmt.Errorf("composer install failed: %v", err)
+	}
+	if temporaryMirror && !hadLock {
+		c.keepMirrorLock(ctx)
+	}
+	return nil
+}
+
+// lockUpdateCommand rewrites the lock file's hash and package URLs for the
+// repositories of the composer.json it runs with, keeping the locked versions
+func (c *createContext) lockUpdateCommand(env ...string) utils.Command {
+	return c.setupCommand(c.binComposer, "composer", []string{"update", "--lock", "--no-install"}, append(composerEnv(), env...)...)
+}
+
+// prepareTemporaryMirror writes a copy of composer.json pointing at the
+// mirror. A composer.lock is copied along and pointed at the mirror too,
+// since composer downloads locked packages from the URLs in the lock. The
+// returned cleanup removes the copies and must be called even on error.
+func (c *createContext) prepareTemporaryMirror(ctx context.Context) (func(), error) {
+	mirrorPath := filepath.Join(c.projectRoot, mirrorComposerFile)
+	mirrorLockPath := filepath.Join(c.projectRoot, mirrorLockFile)
+	cleanup := func() {
+		os.Remove(mirrorPath)
+		os.Remove(mirrorLockPath)
+	}
+
+	if err := utils.ReplaceFile(filepath.Join(c.projectRoot, "composer.json"), mirrorPath); err != nil {
+		return cleanup, err
+	}
+	if err := setComposerMirror(mirrorPath, c.mirror.url); err != nil {
+		return cleanup, err
+	}
+	if !c.hasComposerLock() {
+		return cleanup, nil
+	}
+
+	if err := utils.ReplaceFile(filepath.Join(c.projectRoot, "composer.lock"), mirrorLockPath); err != nil {
+		return cleanup, err
+	}
+	if _, err := utils.Runner().Output(ctx, c.lockUpdateCommand("COMPOSER="+mirrorComposerFile)); err != nil {
+		return cleanup, fmt.Errorf("failed to point composer.lock at the mirror: %v", err)
+	}
+	return cleanup, nil
+}
+
+// keepMirrorLock saves the lock resolved through the temporary mirror as
+// composer.lock, with its URLs pointed back at packagist.org so later
+// installs and the Docker image do not depend on the mirror
+func (c *createContext) keepMirrorLock(ctx context.Context) {
+	lockPath := filepath.Join(c.projectRoot, "composer.lock")
+	err := utils.ReplaceFile(filepath.Join(c.projectRoot, mirrorLockFile), lockPath)
+	if err == nil {
+		if _, err = utils.Runner().Output(ctx, c.lockUpdateCommand()); err != nil {
+			os.Remove(lockPath)
+		}
+	}
+	if err != nil {
+		prompt.Warning(fmt.Sprintf("composer.lock was not written, run composer update --lock to create it: %v", err))
+	}
+}
+
+// hasComposerLock reports whether the project pins its dependencies
+func (c *createContext) hasComposerLock() bool {
+	_, err := os.Stat(filepath.Join(c.projectRoot, "composer.lock"))
+	return err == nil
+}
+
+func (c *createContext) composerInstallCommand() utils.Command {
+	return c.setupCommand(c.binComposer, "composer", []string{"install"}, composerEnv()...)
+}
+
+func (c *createContext) migrateCommand() utils.Command {
+	return c.setupCommand(c.binPhp, "php", []string{"bin/hyperf.php", "migrate"})
+}
+
+func (c *createContext) migrate(ctx context.Context) error {
+	// Run hyperf.php migrate
+	prompt.Info("Running database migrations...")
+	if err := utils.RunCommand(ctx, c.migrateCommand()); err != nil {
+		return fmt.Errorf("database migration failed: %v", err)
+	}
+	return nil
+}
+
+// postInstallCommands returns the dependency commands of the language's project
+func (c *createContext) postInstallCommands() ([]utils.Command, error) {
+	def, err := downloader.DefinitionFor(c.language)
+	if err != nil {
+		return nil, err
+	}
+	var cmds []utils.Command
+	for _, cmd := range def.PostInstall {
+		cmds = append(cmds, c.command(cmd.Name, cmd.Args, cmd.Env...))
+	}
+	return cmds, nil
+}
+
+// installDependencies runs the post-install commands of non-PHP projects
+func (c *createContext) installDependencies(ctx context.Context) error {
+	cmds, err := c.postInstallCommands()
+	if err != nil {
+		return err
+	}
+	for _, cmd := range cmds {
+		if !utils.CheckCommandExists(cmd.Name) {
+			prompt.Info(fmt.Sprintf("Install %s and resume, or run manually in %s: %s", cmd.Name, c.projectRoot, cmd))
+			return fmt.Errorf("command '%s' not found", cmd.Name)
+		}
+		prompt.Info(fmt.Sprintf("Running %s...", cmd))
+		if err := utils.RunCommand(ctx, cmd); err != nil {
+			return fmt.Errorf("%s failed: %v", cmd, err)
+		}
+	}
+	return nil
+}
+
+func (c *createContext) planInstallDependencies(ctx context.Context) error {
+	cmds, err := c.postInstallCommands()
+	if err != nil {
+		return err
+	}
+	for _, cmd := range cmds {
+		printCommandPlan(cmd)
+	}
+	return nil
+}
+
+// printPlan prints the details of a dry-run step
+func printPlan(lines ...string) {
+	for _, line := range lines {
+		prompt.Plain("    " + line)
+	}
+}
+
+func (c *createContext) planDownload(ctx context.Context) error {
+	dl := downloader.NewDownloader(c.language, c.fetchRef(), c.source)
+	printPlan(
+		fmt.Sprintf("download %s", dl.URL()),
+		fmt.Sprintf("extract into %s", c.projectName),
+	)
+	return nil
+}
+
+func (c *createContext) planPlatform(ctx context.Context) error {
+	overlay := c.platformOverlay()
+	if overlay == nil {
+		printPlan(fmt.Sprintf("nothing to do for %s/%s %s", c.language, c.platform, c.version))
+		return nil
+	}
+
+	printOverlayPlan(c.source, c.projectRoot, c.fetchRef(), overlay)
+	return nil
+}
+
+func (c *createContext) planConfigure(ctx context.Context) error {
+	cfg, err := collectConfiguration(c.config)
+	if err != nil {
+		return err
+	}
+	c.applyFeatures(cfg)
+	if c.config.askFeatures && len(cfg.Features) > 0 {
+		printPlan(fmt.Sprintf("enable %s", strings.Join(cfg.Features, ", ")))
+	}
+
+	printPlan(fmt.Sprintf("set the http server port in %s to %s", serverConfigPath(c.projectRoot), cfg.AppPort))
+	// Mask the password fields themselves rather than relying on redaction
+	masked := *cfg
+	for _, f := range masked.fields(0) {
+		if f.secret && *f.value != "" {
+			*f.value = "******"
+		}
+	}
+	printPlan(fmt.Sprintf("write %s:", filepath.Join(c.projectRoot, ".env")))
+	printPlan(strings.Split(strings.TrimRight(renderEnv(&masked), "\n"), "\n")...)
+	return nil
+}
+
+func (c *createContext) planCheckEnvironment(ctx context.Context) error {
+	if c.docker.setup {
+		printPlan("check that docker and docker compose are installed")
+		return nil
+	}
+	printPlan(fmt.Sprintf("check that %s and %s are installed", c.binPhp, c.binComposer))
+	profile, err := platform.Get(c.platform)
+	if err != nil {
+		return err
+	}
+	for _, ext := range profile.Extensions {
+		printPlan(fmt.Sprintf("run %s -m to check the %s extension", c.binPhp, ext.Name))
+	}
+	for _, setting := range profile.Ini {
+		printPlan(fmt.Sprintf("check that %s is %s", setting.Name, setting.Value))
+	}
+	return nil
+}
+
+func (c *createContext) planComposerInstall(ctx context.Context) error {
+	cmd := c.composerInstallCommand()
+	if c.mirror.url != "" {
+		if c.mirror.mode == utils.MirrorPermanent {
+			printPlan(fmt.Sprintf("composer.json: set repositories.packagist to %s", c.mirror.url))
+		} else {
+			printPlan(fmt.Sprintf("write %s using mirror %s (removed after install)", mirrorComposerFile, c.mirror.url))
+			if c.hasComposerLock() {
+				printPlan(fmt.Sprintf("copy composer.lock to %s and point it at the mirror:", mirrorLockFile))
+				printCommandPlan(c.lockUpdateCommand("COMPOSER=" + mirrorComposerFile))
+			}
+			cmd.Env = append(cmd.Env, "COMPOSER="+mirrorComposerFile)
+			printCommandPlan(cmd)
+			if !c.hasComposerLock() {
+				printPlan(fmt.Sprintf("keep %s as composer.lock, pointed back at packagist.org:", mirrorLockFile))
+				printCommandPlan(c.lockUpdateCommand())
+			}
+			return nil
+		}
+	}
+	printCommandPlan(cmd)
+	return nil
+}
+
+func (c *createContext) planMigrate(ctx context.Context) error {
+	printCommandPlan(c.migrateCommand())
+	return nil
+}
+
+// printCommandPlan describes a command a dry-run would execute
+func printCommandPlan(cmd utils.Command) {
+	printPlan(fmt.Sprintf("run %s in %s", cmd, cmd.Dir))
+	for _, env := range cmd.Env {
+		printPlan(fmt.Sprintf("  with %s", env))
+	}
+	if cmd.Timeout > 0 {
+		printPlan(fmt.Sprintf("  timeout %s", cmd.Timeout))
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mineadmin/mine/internal/pipeline"
+	"github.com/mineadmin/mine/internal/prompt"
+)
+
+func TestApplyFeatures(t *testing.T) {
+	c := &createContext{config: configOptions{askFeatures: true}, state: &pipeline.State{}}
+	c.applyFeatures(&projectConfig{Features: []string{featureDocker}})
+
+	if c.seed.seed == nil || *c.seed.seed || c.state.Seed == nil || *c.state.Seed {
+		t.Errorf("seeding enabled without being selected")
+	}
+	if c.frontend.enabled || c.state.Frontend {
+		t.Errorf("frontend enabled without being selected")
+	}
+	if !c.docker.enabled || !c.state.Docker {
+		t.Errorf("docker not enabled although selected")
+	}
+}
+
+func TestOptionalStep(t *testing.T) {
+	prompt.ConfigureOutput(prompt.OutputOptions{Quiet: true})
+	defer prompt.CloseOutput()
+
+	c := &createContext{config: configOptions{askFeatures: true}, state: &pipeline.State{}}
+	ran := 0
+	step := c.optionalStep(pipeline.Step{Name: stepDocker, Run: func(ctx context.Context) error {
+		ran++
+		return nil
+	}}, &c.docker.enabled)
+
+	if err := step.Run(context.Background()); err != nil || ran != 0 {
+		t.Fatalf("step ran %d times before being selected, err %v", ran, err)
+	}
+	c.applyFeatures(&projectConfig{Features: []string{featureDocker}})
+	if err := step.Run(context.Background()); err != nil || ran != 1 {
+		t.Fatalf("step ran %d times after being selected, err %v", ran, err)
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/deploy"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// deployDir is where deployment files are written inside the project
+const deployDir = "deploy"
+
+// NewDeployCmd creates and returns the deploy command
+func NewDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Prepare a project for deployment",
+	}
+	cmd.AddCommand(newDeployInitCmd())
+	return cmd
+}
+
+// deployServerName returns the host of APP_URL for nginx's server_name, or
+// the catch-all name when it is an address or localhost
+func deployServerName(appURL *url.URL) string {
+	host := appURL.Hostname()
+	if host == "" || host == "localhost" || net.ParseIP(host) != nil {
+		return "_"
+	}
+	return host
+}
+
+// normalizeAPIPath checks --api-path and drops its trailing slash, so it
+// can be used as a location prefix; "/" stays as is
+func normalizeAPIPath(path string) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("invalid --api-path %q, it must start with /", path)
+	}
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed, nil
+	}
+	return "/", nil
+}
+
+// currentUser returns the name of the user running mine
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "www-data"
+}
+
+func newDeployInitCmd() *cobra.Command {
+	var (
+		projectDir  string
+		targetNames []string
+		opts        deploy.Options
+		projectPath string
+		outputDir   string
+		force       bool
+		stdout      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate systemd, supervisor or nginx configuration for a project",
+		Long: `Generate deployment files for the Hyperf server of a project: a systemd unit or
+supervisor program running bin/hyperf.php start with --bin-php, and an nginx
+reverse proxy for the port of APP_URL that also serves the built admin UI.
+Files are written to deploy/ in the project, together with the commands that
+install them.
+Example:
+  mine deploy init --target=systemd --target=nginx
+  mine deploy init --target=supervisor --user=www --project-path=/srv/mineadmin --bin-php=/usr/bin/php8.2`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				targets    []deploy.Target
+				proxiesWeb bool
+			)
+			for _, name := range targetNames {
+				target, err := deploy.Get(name)
+				if err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+				targets = append(targets, target)
+				proxiesWeb = proxiesWeb || target.Name == "nginx"
+			}
+
+			env, err := utils.ReadEnvFile(filepath.Join(projectDir, ".env"))
+			if err != nil {
+				prompt.Error(fmt.Sprintf("Failed to read .env: %v", err))
+				os.Exit(1)
+			}
+			appURL, err := url.Parse(env["APP_URL"])
+			if err != nil {
+				prompt.Error(fmt.Sprintf("Invalid APP_URL %q: %v", env["APP_URL"], err))
+				os.Exit(1)
+			}
+			opts.AppPort = appURL.Port()
+			if opts.AppPort == "" {
+				opts.AppPort = defaultAppPort
+			}
+			if !cmd.Flags().Changed("server-name") {
+				opts.ServerName = deployServerName(appURL)
+			}
+
+			if projectPath == "" {
+				if projectPath, err = filepath.Abs(projectDir); err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+			}
+			opts.ProjectDir = filepath.ToSlash(projectPath)
+			if opts.Name == "" {
+				opts.Name = projectSlug(projectDir)
+			}
+			if opts.User == "" {
+				opts.User = currentUser()
+			}
+			if opts.LogDir == "" {
+				opts.LogDir = opts.ProjectDir + "/runtime/logs"
+			}
+			if opts.APIPath, err = normalizeAPIPath(opts.APIPath); err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			if opts.APIPath == "/" {
+				// The server owns every path, there is none left for the admin UI
+				if opts.FrontendDist != "" && proxiesWeb {
+					prompt.Warning("--api-path=/ proxies every request to the server, ignoring --frontend-dist")
+				}
+				opts.FrontendDist = ""
+			} else if !cmd.Flags().Changed("frontend-dist") {
+				if _, err := os.Stat(filepath.Join(projectDir, frontendDir, "package.json")); err == nil {
+					opts.FrontendDist = opts.ProjectDir + "/" + frontendDir + "/dist"
+					if _, err := os.Stat(filepath.Join(projectDir, frontendDir, "dist")); err != nil && proxiesWeb {
+						prompt.Warning(fmt.Sprintf("The admin UI is not built yet, run the build in %s before reloading nginx", frontendDir))
+					}
+				}
+			}
+
+			// systemd and supervisor need an absolute binary path
+			opts.BinPhp, _ = cmd.Flags().GetString("bin-php")
+			if resolved, err := exec.LookPath(opts.BinPhp); err == nil {
+				if abs, err := filepath.Abs(resolved); err == nil {
+					opts.BinPhp = abs
+				}
+			} else if !filepath.IsAbs(opts.BinPhp) {
+				prompt.Warning(fmt.Sprintf("PHP binary %s not found, pass its path on the server with --bin-php", opts.BinPhp))
+			}
+
+			if outputDir == "" {
+				outputDir = filepath.Join(projectDir, deployDir)
+			}
+			for _, target := range targets {
+				content, err := target.Render(opts)
+				if err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+				if stdout {
+					fmt.Print(content)
+					continue
+				}
+
+				path := filepath.Join(outputDir, target.FileName(opts.Name))
+				written, err := writeGeneratedFile(path, content, force)
+				if err != nil {
+					prompt.Error(fmt.Sprintf("Failed to write %s: %v", path, err))
+					os.Exit(1)
+				}
+				if !written {
+					prompt.Warning(fmt.Sprintf("Kept existing %s, pass --force to overwrite", path))
+					continue
+				}
+				prompt.Success(fmt.Sprintf("Generated %s (%s)", path, target.Description))
+				prompt.Info("Install it on the server with:")
+				printPlan(target.InstallSteps(path, opts.Name)...)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&projectDir, "dir", "d", ".", "Project directory")
+	cmd.Flags().StringSliceVarP(&targetNames, "target", "t", nil, "What to generate ("+strings.Join(deploy.Names(), "/")+"), repeatable")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Service name (default: the project directory name)")
+	cmd.Flags().StringVar(&projectPath, "project-path", "", "Project path on the server (default: the absolute --dir)")
+	cmd.Flags().StringVar(&opts.User, "user", "", "User the server runs as (default: the current user)")
+	cmd.Flags().StringVar(&opts.LogDir, "log-dir", "", "Directory of the supervisor log (default: runtime/logs in the project)")
+	cmd.Flags().IntVar(&opts.OpenFiles, "open-files", 65535, "Open file limit of the server process")
+	cmd.Flags().StringVar(&opts.ServerName, "server-name", "", "nginx server_name (default: the host of APP_URL, or _ for an address)")
+	cmd.Flags().StringVar(&opts.FrontendDist, "frontend-dist", "", "Built admin UI served by nginx (default: web/dist when the project has a frontend, empty to proxy everything)")
+	cmd.Flags().StringVar(&opts.APIPath, "api-path", "/api", "Location nginx proxies to the server when it also serves the admin UI (/ proxies everything)")
+	cmd.Flags().StringVarP(&outputDir, "output", "o", "", "Directory to write the files to (default: deploy/ in the project)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+	cmd.Flags().BoolVar(&stdout, "stdout", false, "Print the files instead of writing them")
+	cmd.MarkFlagRequired("target")
+	cmd.RegisterFlagCompletionFunc("target", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return deploy.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/docker"
+	"github.com/mineadmin/mine/internal/pipeline"
+	"github.com/mineadmin/mine/internal/platform"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// devcontainerPostCreate returns the setup steps of create that the dev
+// container runs once it is created: composer install, migrations and,
+// when the project was seeded, the seeders
+func devcontainerPostCreate(projectDir string) []string {
+	commands := []string{"composer install", "php bin/hyperf.php migrate"}
+	if state, err := pipeline.LoadState(projectDir); err == nil && state.Seed != nil && *state.Seed {
+		commands = append(commands, "php bin/hyperf.php db:seed")
+	}
+	return commands
+}
+
+// NewDevcontainerCmd creates and returns the devcontainer command
+func NewDevcontainerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "devcontainer",
+		Short: "Develop a project in a dev container",
+	}
+	cmd.AddCommand(newDevcontainerInitCmd())
+	return cmd
+}
+
+func newDevcontainerInitCmd() *cobra.Command {
+	var (
+		projectDir   string
+		platformName string
+		opts         dockerOptions
+		force        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a dev container configuration for a project",
+		Long: `Generate .devcontainer/devcontainer.json with a development image for the
+project's platform and PHP version. The container extends the compose.yaml of
+mine docker init (generated too when missing), so the database and Redis run
+as side services configured from .env. The server port of APP_URL is forwarded
+and composer install and migrations run when the container is created.
+Example:
+  mine devcontainer init
+  mine devcontainer init --dir=my-project --php-version=8.3`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			if platformName == "" {
+				if platformName, err = projectPlatform(projectDir); err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+			}
+			dockerOpts, err := dockerProjectOptions(projectDir, platformName, opts)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			files, err := docker.Files(dockerOpts)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			devFiles, err := docker.DevcontainerFiles(dockerOpts, devcontainerPostCreate(projectDir))
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+
+			// The project's compose file is only created when missing; --force
+			// regenerates the dev container files alone
+			written, _, err := writeDockerFiles(projectDir, files, false)
+			if err != nil {
+				prompt.Error(fmt.Sprintf("Failed to write Docker files: %v", err))
+				os.Exit(1)
+			}
+			devWritten, skipped, err := writeDockerFiles(projectDir, devFiles, force)
+			if err != nil {
+				prompt.Error(fmt.Sprintf("Failed to write dev container files: %v", err))
+				os.Exit(1)
+			}
+			if len(skipped) > 0 {
+				prompt.Warning(fmt.Sprintf("Kept existing %s, pass --force to overwrite", strings.Join(skipped, ", ")))
+			}
+			written = append(written, devWritten...)
+			if len(written) == 0 {
+				return
+			}
+			prompt.Success(fmt.Sprintf("Generated %s for %s", strings.Join(written, ", "), dockerOpts.BaseImage()))
+			prompt.Info("Open the project in an editor with dev container support and choose \"Reopen in Container\"")
+		},
+	}
+
+	cmd.Flags().StringVarP(&projectDir, "dir", "d", ".", "Project directory")
+	cmd.Flags().StringVarP(&platformName, "platform", "p", "", "Platform of the image (read from .mine/state.json or composer.json by default)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing dev container files")
+	addDockerFlags(cmd, &opts)
+	cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return platform.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mineadmin/mine/internal/composer"
+	"github.com/mineadmin/mine/internal/docker"
+	"github.com/mineadmin/mine/internal/pipeline"
+	"github.com/mineadmin/mine/internal/platform"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// dockerOptions controls the Docker files generated for a project
+type dockerOptions struct {
+	enabled bool
+	// setup runs composer install, migrations and seeders in the app container
+	setup      bool
+	phpVersion string
+	image      string
+}
+
+// addDockerFlags registers the flags shared by create and docker init
+func addDockerFlags(cmd *cobra.Command, opts *dockerOptions) {
+	cmd.Flags().StringVar(&opts.phpVersion, "php-version", "", "PHP version of the Docker base image (default: from composer.json require.php, else "+docker.DefaultPHPVersion+")")
+	cmd.Flags().StringVar(&opts.image, "image", "", "Docker base image, instead of the platform's hyperf/hyperf image")
+}
+
+// projectPlatform returns the platform recorded for a project, or the one
+// its composer.json targets when it was not created by mine
+func projectPlatform(projectDir string) (string, error) {
+	state, err := pipeline.LoadState(projectDir)
+	if err == nil && state.Platform != "" {
+		return state.Platform, nil
+	}
+	version := ""
+	if err == nil {
+		version = state.Version
+	}
+	name, err := detectPlatform(projectDir, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to read composer.json: %v", err)
+	}
+	return name, nil
+}
+
+// phpVersionPattern finds the first major.minor version of a constraint
+var phpVersionPattern = regexp.MustCompile(`\d+\.\d+`)
+
+// projectPHPVersion returns the lowest PHP version composer.json allows,
+// such as "8.1" for ">=8.1", or "" when it does not say
+func projectPHPVersion(projectRoot string) string {
+	f, err := composer.Load(filepath.Join(projectRoot, "composer.json"))
+	if err != nil {
+		return ""
+	}
+	var constraint string
+	if ok, err := f.Get([]string{composer.SectionRequire, "php"}, &constraint); !ok || err != nil {
+		return ""
+	}
+	return phpVersionPattern.FindString(constraint)
+}
+
+// dockerProjectOptions describes a project from its .env for docker.Files
+func dockerProjectOptions(projectRoot, platformName string, opts dockerOptions) (docker.Options, error) {
+	profile, err := platform.Get(platformName)
+	if err != nil {
+		return docker.Options{}, err
+	}
+	env, err := utils.ReadEnvFile(filepath.Join(projectRoot, ".env"))
+	if err != nil {
+		return docker.Options{}, fmt.Errorf("failed to read .env: %v", err)
+	}
+
+	o := docker.Options{
+		ProjectName:     projectSlug(projectRoot),
+		Profile:         profile,
+		PHPVersion:      opts.phpVersion,
+		Image:           opts.image,
+		AppPort:         defaultAppPort,
+		DBDriver:        env["DB_DRIVER"],
+		DBUser:          env["DB_USERNAME"],
+		EmptyDBPassword: env["DB_PASSWORD"] == "",
+		RedisPassword:   env["REDIS_AUTH"] != "",
+	}
+	if appURL, err := url.Parse(env["APP_URL"]); err == nil && appURL.Port() != "" {
+		o.AppPort = appURL.Port()
+	}
+	if o.DBDriver == "" {
+		o.DBDriver = "mysql"
+	}
+	if o.PHPVersion == "" {
+		o.PHPVersion = projectPHPVersion(projectRoot)
+	}
+	return o, nil
+}
+
+// projectSlug names the project in generated files such as the compose project
+// or a service: the directory name in lowercase letters, digits, dashes and underscores
+func projectSlug(projectRoot string) string {
+	abs, err := filepath.Abs(projectRoot)
+	if err != nil {
+		abs = projectRoot
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, filepath.Base(abs))
+	return strings.Trim(name, "-_")
+}
+
+// writeDockerFiles writes the generated files into projectRoot. Existing
+// files are kept and returned as skipped unless force is set.
+func writeDockerFiles(projectRoot string, files []docker.File, force bool) (written, skipped []string, err error) {
+	for _, f := range files {
+		ok, err := writeGeneratedFile(filepath.Join(projectRoot, f.Path), f.Content, force)
+		if err != nil {
+			return written, skipped, err
+		}
+		if ok {
+			written = append(written, f.Path)
+		} else {
+			skipped = append(skipped, f.Path)
+		}
+	}
+	return written, skipped, nil
+}
+
+// writeGeneratedFile writes content to path, creating its directory, and
+// reports whether it did. An existing file is only replaced when force is set.
+func writeGeneratedFile(path, content string, force bool) (bool, error) {
+	if _, err := os.Stat(path); err == nil && !force {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+// composeRun wraps cmd to run in the app container. Environment entries
+// are forwarded by name so their values stay out of the process list.
+func composeRun(cmd utils.Command) utils.Command {
+	// The hyperf images run as root
+	args := []string{"compose", "run", "--rm", "-T", "-e", "COMPOSER_ALLOW_SUPERUSER=1"}
+	for _, env := range cmd.Env {
+		if key, _, _ := strings.Cut(env, "="); key != "COMPOSER_ALLOW_SUPERUSER" {
+			args = append(args, "-e", key)
+		}
+	}
+	args = append(args, docker.ServiceApp, cmd.Name)
+	return utils.Command{
+		Name:    "docker",
+		Args:    append(args, cmd.Args...),
+		Dir:     cmd.Dir,
+		Env:     cmd.Env,
+		Timeout: cmd.Timeout,
+	}
+}
+
+// checkDocker verifies that docker and the compose plugin are installed
+func checkDocker(ctx context.Context) error {
+	if !utils.CheckCommandExists("docker") {
+		return fmt.Errorf("docker command not found")
+	}
+	if _, err := utils.Runner().Output(ctx, utils.Command{Name: "docker", Args: []string{"compose", "version"}}); err != nil {
+		return fmt.Errorf("docker compose is not available: %v", err)
+	}
+	return nil
+}
+
+// generateDocker writes the Docker files for the new project
+func (c *createContext) generateDocker(ctx context.Context) error {
+	opts, err := dockerProjectOptions(c.projectRoot, c.platform, c.docker)
+	if err != nil {
+		return err
+	}
+	files, err := docker.Files(opts)
+	if err != nil {
+		return err
+	}
+	written, skipped, err := writeDockerFiles(c.projectRoot, files, false)
+	if err != nil {
+		return fmt.Errorf("failed to write Docker files: %v", err)
+	}
+	for _, path := range skipped {
+		prompt.Warning(fmt.Sprintf("Keeping existing %s", path))
+	}
+	if len(written) > 0 {
+		prompt.Success(fmt.Sprintf("Generated %s for %s", strings.Join(written, ", "), opts.BaseImage()))
+	}
+	return nil
+}
+
+func (c *createContext) planDocker(ctx context.Context) error {
+	profile, err := platform.Get(c.platform)
+	if err != nil {
+		return err
+	}
+	image := c.docker.image
+	switch {
+	case image != "":
+	case c.docker.phpVersion != "":
+		image = profile.ImageFor(c.docker.phpVersion)
+	default:
+		image = profile.ImageFor("<php>") + ", <php> being the version composer.json requires"
+	}
+	printPlan(
+		fmt.Sprintf("write %s based on %s", filepath.Join(c.projectRoot, docker.Dockerfile), image),
+		fmt.Sprintf("write %s with %s, %s and %s services using the values in .env", filepath.Join(c.projectRoot, docker.ComposeFile), docker.ServiceApp, docker.ServiceDB, docker.ServiceRedis),
+		fmt.Sprintf("write %s", filepath.Join(c.projectRoot, docker.DockerIgnore)),
+	)
+	return nil
+}
+
+// NewDockerCmd creates and returns the docker command
+func NewDockerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker",
+		Short: "Run a project in Docker",
+	}
+	cmd.AddCommand(newDockerInitCmd())
+	return cmd
+}
+
+func newDockerInitCmd() *cobra.Command {
+	var (
+		projectDir   string
+		platformName string
+		opts         dockerOptions
+		force        bool
+		commands     commandOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a Dockerfile and compose file for an existing project",
+		Long: `Generate a Dockerfile for the project's platform and a compose.yaml with the
+app, a MySQL or PostgreSQL database and Redis, wired to the values in .env.
+With --setup, composer install and migrations then run inside the app container.
+Example:
+  mine docker init
+  mine docker init --dir=my-project --php-version=8.3 --setup`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			if platformName == "" {
+				if platformName, err = projectPlatform(projectDir); err != nil {
+					prompt.Error(err.Error())
+					os.Exit(1)
+				}
+			}
+			dockerOpts, err := dockerProjectOptions(projectDir, platformName, opts)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			files, err := docker.Files(dockerOpts)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+
+			written, skipped, err := writeDockerFiles(projectDir, files, force)
+			if err != nil {
+				prompt.Error(fmt.Sprintf("Failed to write Docker files: %v", err))
+				os.Exit(1)
+			}
+			if len(skipped) > 0 {
+				prompt.Warning(fmt.Sprintf("Kept existing %s, pass --force to overwrite", strings.Join(skipped, ", ")))
+			}
+			if len(written) > 0 {
+				prompt.Success(fmt.Sprintf("Generated %s for %s", strings.Join(written, ", "), dockerOpts.BaseImage()))
+			}
+			if !opts.setup {
+				prompt.Info(fmt.Sprintf("Start the project with: docker compose up (in %s)", projectDir))
+				return
+			}
+
+			if err := checkDocker(cmd.Context()); err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			for _, args := range [][]string{{"composer", "install"}, {"php", "bin/hyperf.php", "migrate"}} {
+				run := composeRun(utils.Command{
+					Name:    args[0],
+					Args:    args[1:],
+					Dir:     projectDir,
+					Env:     append(composerEnv(), commands.env...),
+					Timeout: commands.timeout,
+				})
+				prompt.Info(fmt.Sprintf("Running %s...", run))
+				if err := utils.RunCommand(cmd.Context(), run); err != nil {
+					prompt.Error(fmt.Sprintf("%s failed: %v", run, err))
+					os.Exit(1)
+				}
+			}
+			prompt.Success("Project set up, start it with: docker compose up")
+		},
+	}
+
+	cmd.Flags().StringVarP(&projectDir, "dir", "d", ".", "Project directory")
+	cmd.Flags().StringVarP(&platformName, "platform", "p", "", "Platform of the image (read from .mine/state.json or composer.json by default)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing Docker files")
+	cmd.Flags().BoolVar(&opts.setup, "setup", false, "Run composer install and migrations inside the app container")
+	cmd.Flags().DurationVar(&commands.timeout, "command-timeout", 30*time.Minute, "Timeout for each setup command (0 for none)")
+	cmd.Flags().StringArrayVar(&commands.env, "env", nil, "Extra KEY=VALUE environment for setup commands (repeatable)")
+	addDockerFlags(cmd, &opts)
+	cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return platform.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
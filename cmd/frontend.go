@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+const (
+	// frontendDir is where MineAdmin ships its admin UI
+	frontendDir = "web"
+	// frontendEnvFile is the env file read by the Vite dev server
+	frontendEnvFile = ".env.development"
+	// minNodeMajor is used when package.json declares no engines.node
+	minNodeMajor = 18
+	// defaultAppURL is the backend URL MineAdmin listens on out of the box
+	defaultAppURL = "http://127.0.0.1:" + defaultAppPort
+)
+
+// frontendOptions controls the optional admin UI setup
+type frontendOptions struct {
+	enabled  bool
+	registry string
+	binNode  string
+}
+
+func (c *createContext) frontendRoot() string {
+	return filepath.Join(c.projectRoot, frontendDir)
+}
+
+// frontendInstallCommand returns the install command of the detected package manager
+func (c *createContext) frontendInstallCommand() utils.Command {
+	manager := utils.DetectPackageManager(c.frontendRoot())
+	cmd := c.command(manager, []string{"install"}, utils.NpmRegistryEnv(c.frontend.registry)...)
+	cmd.Dir = c.frontendRoot()
+	return cmd
+}
+
+// backendAppURL returns APP_URL from the backend .env so the UI talks to the
+// port chosen during configuration
+func (c *createContext) backendAppURL() string {
+	env, err := utils.ReadEnvFile(filepath.Join(c.projectRoot, ".env"))
+	if err != nil || env["APP_URL"] == "" {
+		return defaultAppURL
+	}
+	return env["APP_URL"]
+}
+
+// setupFrontend installs the admin UI dependencies and points it at the backend
+func (c *createContext) setupFrontend(ctx context.Context) error {
+	root := c.frontendRoot()
+	if _, err := os.Stat(filepath.Join(root, "package.json")); err != nil {
+		return fmt.Errorf("no frontend found in %s", root)
+	}
+
+	// Check Node and the package manager before installing anything
+	if !utils.CheckCommandExists(c.frontend.binNode) {
+		return fmt.Errorf("Node command '%s' not found", c.frontend.binNode)
+	}
+	required := utils.RequiredNodeMajor(root, minNodeMajor)
+	spinner := prompt.StartSpinner("Checking Node version...")
+	major, err := utils.NodeMajorVersion(ctx, c.frontend.binNode)
+	spinner.Stop()
+	if err != nil {
+		return err
+	}
+	if major < required {
+		return fmt.Errorf("Node %d is too old, the frontend requires Node %d or newer", major, required)
+	}
+
+	cmd := c.frontendInstallCommand()
+	if !utils.CheckCommandExists(cmd.Name) {
+		return fmt.Errorf("package manager '%s' not found, install it or run '%s install' in %s manually", cmd.Name, cmd.Name, root)
+	}
+
+	prompt.Info(fmt.Sprintf("Running %s install in %s...", cmd.Name, frontendDir))
+	if err := utils.RunCommand(ctx, cmd); err != nil {
+		return fmt.Errorf("frontend install failed: %v", err)
+	}
+
+	appURL := c.backendAppURL()
+	envPath := filepath.Join(root, frontendEnvFile)
+	values := map[string]string{"VITE_APP_API_BASEURL": appURL}
+	if err := utils.SetEnvValues(envPath, values, []string{"VITE_APP_API_BASEURL"}); err != nil {
+		return fmt.Errorf("failed to write %s: %v", envPath, err)
+	}
+	prompt.Success(fmt.Sprintf("Frontend configured to use API %s", appURL))
+	return nil
+}
+
+func (c *createContext) planFrontend(ctx context.Context) error {
+	printPlan(fmt.Sprintf("check %s is Node %d or newer (or as required by %s/package.json)", c.frontend.binNode, minNodeMajor, frontendDir))
+	printCommandPlan(c.frontendInstallCommand())
+	printPlan(fmt.Sprintf("set VITE_APP_API_BASEURL to the backend APP_URL in %s", filepath.Join(c.frontendRoot(), frontendEnvFile)))
+	return nil
+}
@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mineadmin/mine/internal/composer"
+	"github.com/mineadmin/mine/internal/pipeline"
+	"github.com/mineadmin/mine/internal/platform"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/source"
+	"github.com/mineadmin/mine/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// applyOverlay fetches the overlay files at version from src and writes them into the
+// project, then edits composer.json and returns the edits made. Everything is downloaded before anything
+// is written. Modified files are copied to backupDir first unless it is empty.
+func applyOverlay(ctx context.Context, src source.Source, projectRoot, version string, overlay *platform.Overlay, backupDir string) ([]composer.Change, error) {
+	contents := make([][]byte, len(overlay.Files))
+	for i, file := range overlay.Files {
+		content, err := src.File(ctx, version, file.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s from %s: %v", file.Source, src.Name(), err)
+		}
+		contents[i] = content
+	}
+
+	if backupDir != "" {
+		paths := []string{"composer.json", "composer.lock"}
+		for _, file := range overlay.Files {
+			paths = append(paths, filepath.FromSlash(file.Target))
+		}
+		if err := backupFiles(projectRoot, backupDir, paths); err != nil {
+			return nil, fmt.Errorf("failed to back up project files: %v", err)
+		}
+	}
+
+	for i, file := range overlay.Files {
+		dstPath := filepath.Join(projectRoot, filepath.FromSlash(file.Target))
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %v", dstPath, err)
+		}
+		if err := ioutil.WriteFile(dstPath, contents[i], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", dstPath, err)
+		}
+	}
+
+	changes, err := editComposerJSON(projectRoot, func(f *composer.File) error {
+		return composer.ApplyPackageChanges(f, overlay.Composer)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify composer.json: %v", err)
+	}
+	return changes, nil
+}
+
+// printOverlayPlan describes what applyOverlay would do
+func printOverlayPlan(src source.Source, projectRoot, version string, overlay *platform.Overlay) {
+	for _, file := range overlay.Files {
+		printPlan(fmt.Sprintf("replace %s with %s", filepath.Join(projectRoot, filepath.FromSlash(file.Target)),
+			src.FileURL(version, file.Source)))
+	}
+	for _, change := range overlay.Composer {
+		printPlan(fmt.Sprintf("composer.json: %s", change))
+	}
+}
+
+// backupFiles copies the existing files among paths into backupDir, keeping
+// their project-relative layout
+func backupFiles(projectRoot, backupDir string, paths []string) error {
+	for _, path := range paths {
+		src := filepath.Join(projectRoot, path)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := utils.ReplaceFile(src, filepath.Join(backupDir, path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPlatformRequirements verifies the PHP extensions and ini settings a
+// platform profile requires, printing how to fix what is missing
+func checkPlatformRequirements(ctx context.Context, binPhp string, profile platform.Profile) error {
+	for _, ext := range profile.Extensions {
+		spinner := prompt.StartSpinner(fmt.Sprintf("Checking %s extension...", ext.Name))
+		extExists, err := utils.CheckPhpExtension(ctx, binPhp, ext.Name)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to check %s extension: %v", ext.Name, err)
+		}
+		if !extExists {
+			prompt.Info(fmt.Sprintf("Install the %s extension: %s", ext.Name, ext.URL))
+			return fmt.Errorf("%s extension is not installed", ext.Name)
+		}
+	}
+	for _, setting := range profile.Ini {
+		value, err := utils.PhpIniValue(ctx, binPhp, setting.Name)
+		if err != nil {
+			return err
+		}
+		if !setting.Matches(value) {
+			prompt.Info(fmt.Sprintf("Add %s=%s to your php.ini", setting.Name, setting.Value))
+			return fmt.Errorf("%s platform needs %s=%s, found %q", profile.Name, setting.Name, setting.Value, value)
+		}
+	}
+	return nil
+}
+
+// detectPlatform reports the platform a project's composer.json targets: the
+// first profile whose overlay packages are all required, else the upstream one
+func detectPlatform(projectRoot, version string) (string, error) {
+	f, err := composer.Load(filepath.Join(projectRoot, "composer.json"))
+	if err != nil {
+		return "", err
+	}
+	upstream := ""
+	for _, profile := range platform.All() {
+		if profile.Upstream {
+			upstream = profile.Name
+			continue
+		}
+		overlay := profile.OverlayFor(version)
+		if overlay == nil {
+			continue
+		}
+		matched := true
+		for _, change := range overlay.Composer {
+			if change.Version != "" && !f.Has(change.Section, change.Package) {
+				matched = false
+			}
+		}
+		if matched {
+			return profile.Name, nil
+		}
+	}
+	return upstream, nil
+}
+
+// restoreServerPort writes the port of APP_URL in .env back into server.php,
+// since the replaced server.php carries the upstream default port
+func restoreServerPort(projectRoot string) error {
+	env, err := utils.ReadEnvFile(filepath.Join(projectRoot, ".env"))
+	if err != nil || env["APP_URL"] == "" {
+		return nil
+	}
+	appURL, err := url.Parse(env["APP_URL"])
+	if err != nil || appURL.Port() == "" {
+		return nil
+	}
+	port, err := strconv.Atoi(appURL.Port())
+	if err != nil {
+		return nil
+	}
+	if err := utils.SetServerPort(serverConfigPath(projectRoot), port); err != nil {
+		return fmt.Errorf("failed to set server port: %v", err)
+	}
+	return nil
+}
+
+// NewPlatformCmd creates and returns the platform command
+func NewPlatformCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "platform",
+		Short: "Manage the runtime platform of a project",
+	}
+	cmd.AddCommand(newPlatformListCmd())
+	cmd.AddCommand(newPlatformSwitchCmd())
+	return cmd
+}
+
+// newPlatformListCmd creates the platform list subcommand
+func newPlatformListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the available platform profiles",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, profile := range platform.All() {
+				fmt.Printf("%s - %s\n", color.New(color.Bold).Sprint(profile.Name), profile.Description)
+
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				var exts []string
+				for _, ext := range profile.Extensions {
+					exts = append(exts, ext.Name)
+				}
+				fmt.Fprintf(w, "  extensions\t%s\n", listOrNone(exts))
+				var ini []string
+				for _, setting := range profile.Ini {
+					ini = append(ini, setting.Name+"="+setting.Value)
+				}
+				fmt.Fprintf(w, "  ini\t%s\n", listOrNone(ini))
+				for _, overlay := range profile.Overlays {
+					label := "overlay " + overlay.Versions
+					if profile.Upstream {
+						label += " (switch only)"
+					}
+					var files []string
+					for _, file := range overlay.Files {
+						files = append(files, file.Target)
+					}
+					fmt.Fprintf(w, "  %s\tfiles: %s\n", label, listOrNone(files))
+					for _, change := range overlay.Composer {
+						fmt.Fprintf(w, "  \tcomposer.json: %s\n", change)
+					}
+				}
+				w.Flush()
+				fmt.Println()
+			}
+		},
+	}
+}
+
+// listOrNone joins items for display
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// newPlatformSwitchCmd creates the platform switch subcommand
+func newPlatformSwitchCmd() *cobra.Command {
+	var (
+		projectDir string
+		version    string
+		ref        string
+		repo       string
+		noUpdate   bool
+		commands   commandOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:       "switch <platform>",
+		Short:     "Switch an existing project to another platform",
+		ValidArgs: platform.Names(),
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		Long: `Switch an existing MineAdmin project to another platform (see mine platform list).
+The files of the target platform's overlay are fetched for the project's version,
+composer.json is updated, and composer update is run for the affected packages.
+Modified files are backed up to .mine/backups first.
+Example:
+  mine platform switch swoole
+  mine platform switch swow --dir=my-project --version=v3.0.1`,
+		Run: func(cmd *cobra.Command, args []string) {
+			target := args[0]
+			binPhp, _ := cmd.Flags().GetString("bin-php")
+			binComposer, _ := cmd.Flags().GetString("bin-composer")
+
+			// The state file supplies the version, ref and repository a project was created from
+			state, stateErr := pipeline.LoadState(projectDir)
+			if stateErr == nil {
+				if version == "" {
+					version = state.Version
+				}
+				if ref == "" {
+					ref = state.Ref
+				}
+				if repo == "" {
+					repo = state.Repo
+				}
+			}
+			if version == "" && ref == "" {
+				prompt.Error("Cannot tell the project's MineAdmin version, pass it with --version or --ref")
+				os.Exit(1)
+			}
+			fetchRef := ref
+			if fetchRef == "" {
+				fetchRef = version
+			}
+			profile, err := platform.Get(target)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			overlay := profile.OverlayFor(version)
+			if overlay == nil {
+				prompt.Error(fmt.Sprintf("The %s platform has no overlay for MineAdmin %s", target, fetchRef))
+				os.Exit(1)
+			}
+
+			current, err := detectPlatform(projectDir, version)
+			if err != nil {
+				prompt.Error(fmt.Sprintf("Failed to read composer.json: %v", err))
+				os.Exit(1)
+			}
+			if current == target {
+				prompt.Info(fmt.Sprintf("Project already uses %s", target))
+				return
+			}
+
+			// Check the requirements before touching any file
+			if err := checkPlatformRequirements(cmd.Context(), binPhp, profile); err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+
+			src, err := resolveSource("php", repo)
+			if err != nil {
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+
+			backupDir := filepath.Join(projectDir, pipeline.StateDir, "backups", time.Now().Format("20060102-150405"))
+			spinner := prompt.StartSpinner(fmt.Sprintf("Switching project from %s to %s...", current, target))
+			changes, err := applyOverlay(cmd.Context(), src, projectDir, fetchRef, overlay, backupDir)
+			if err != nil {
+				spinner.Fail()
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			if err := restoreServerPort(projectDir); err != nil {
+				spinner.Fail()
+				prompt.Error(err.Error())
+				os.Exit(1)
+			}
+			spinner.Done()
+			for _, change := range changes {
+				prompt.Info(fmt.Sprintf("composer.json: %s", change))
+			}
+			prompt.Info(fmt.Sprintf("Previous files backed up to %s", backupDir))
+
+			if stateErr == nil {
+				state.Platform = target
+				if err := state.Save(projectDir); err != nil {
+					prompt.Warning(fmt.Sprintf("Failed to update project state: %v", err))
+				}
+			}
+
+			if noUpdate {
+				prompt.Success(fmt.Sprintf("Project switched to %s, run composer update to finish", target))
+				return
+			}
+			prompt.Info("Running composer update...")
+			update := utils.Command{
+				Name:    binComposer,
+				Args:    composerUpdateArgs(overlay.Composer),
+				Dir:     projectDir,
+				Env:     append(composerEnv(), commands.env...),
+				Timeout: commands.timeout,
+			}
+			if err := utils.RunCommand(cmd.Context(), update); err != nil {
+				prompt.Error(fmt.Sprintf("composer update failed: %v", err))
+				prompt.Info(fmt.Sprintf("Restore the previous files from %s or fix the problem and run: %s", backupDir, update))
+				os.Exit(1)
+			}
+			prompt.Success(fmt.Sprintf("Project switched to %s", target))
+		},
+	}
+
+	cmd.Flags().StringVarP(&projectDir, "dir", "d", ".", "Project directory")
+	cmd.Flags().StringVarP(&version, "version", "v", "", "MineAdmin version of the project (read from .mine/state.json by default)")
+	cmd.Flags().StringVar(&ref, "ref", "", "Fetch the platform files at this branch or commit (read from .mine/state.json by default)")
+	cmd.Flags().StringVar(&repo, "repo", "", "Fetch the platform files from this repository, as owner/name")
+	cmd.Flags().BoolVar(&noUpdate, "no-update", false, "Only edit files, do not run composer update")
+	cmd.Flags().DurationVar(&commands.timeout, "command-timeout", 30*time.Minute, "Timeout for composer update (0 for none)")
+	cmd.Flags().StringArrayVar(&commands.env, "env", nil, "Extra KEY=VALUE environment for composer (repeatable)")
+
+	return cmd
+}
+
+// composerUpdateArgs updates only the packages touched by changes. Platform
+// requirements such as ext-swoole have no package to update, so when nothing
+// else changed only the lock file hash is refreshed.
+func composerUpdateArgs(changes []composer.PackageChange) []string {
+	args := []string{"update"}
+	for _, change := range changes {
+		if !strings.HasPrefix(change.Package, "ext-") && change.Package != "php" {
+			args = append(args, change.Package)
+		}
+	}
+	if len(args) == 1 {
+		return append(args, "--lock")
+	}
+	return append(args, "--with-all-dependencies")
+}
@@ -1,14 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/mineadmin/mine/internal/prompt"
 	"github.com/spf13/cobra"
 )
 
 func NewRootCmd() *cobra.Command {
-	var binPhp string
+	var (
+		binPhp string
+		output prompt.OutputOptions
+	)
 
 	rootCmd := &cobra.Command{
 		Use:   "mine",
@@ -32,31 +39,82 @@ A powerful command line tool for downloading and managing MineAdmin projects.
 🔹 Commands:
   - create: Create a new MineAdmin project
   - select-versions: List available MineAdmin versions
+  - platform switch: Switch a project between Swow and Swoole
+  - docker init: Generate a Dockerfile and compose file for a project
+  - devcontainer init: Generate a dev container configuration for a project
+  - deploy init: Generate systemd, supervisor or nginx configuration
 
 🔹 Examples:
   mine create my-project
   mine select-versions
 
 Complete documentation is available at https://github.com/mineadmin/mine`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := prompt.ConfigureOutput(output); err != nil {
+				return err
+			}
+
+			// Answer prompts from a file instead of the terminal when requested
+			answersFile, _ := cmd.Flags().GetString("answers-file")
+			if answersFile != "" {
+				scripted, err := prompt.NewScriptedPrompterFromFile(answersFile)
+				if err != nil {
+					return err
+				}
+				prompt.SetPrompter(scripted)
+			}
+			return nil
+		},
 	}
 
 	// Add global flags
 	rootCmd.PersistentFlags().StringVar(&binPhp, "bin-php", "php", "PHP binary path")
 	var binComposer string
 	rootCmd.PersistentFlags().StringVar(&binComposer, "bin-composer", "composer", "Composer binary path")
+	var answersFile string
+	rootCmd.PersistentFlags().StringVar(&answersFile, "answers-file", "", "JSON file of prompt answers keyed by prompt label")
+	rootCmd.PersistentFlags().BoolVarP(&output.Quiet, "quiet", "q", false, "Only print errors")
+	rootCmd.PersistentFlags().BoolVar(&output.Verbose, "verbose", false, "Print debug messages")
+	rootCmd.PersistentFlags().BoolVar(&output.NoColor, "no-color", false, "Disable colored output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&output.LogFile, "log-file", "", "Write a timestamped transcript of every step and command output to a file")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
 
 	// Add all subcommands
 	rootCmd.AddCommand(NewCreateCmd())
 	rootCmd.AddCommand(NewSelectVersionsCmd())
+	rootCmd.AddCommand(NewPlatformCmd())
+	rootCmd.AddCommand(NewDockerCmd())
+	rootCmd.AddCommand(NewDevcontainerCmd())
+	rootCmd.AddCommand(NewDeployCmd())
 
 	return rootCmd
 }
 
 var rootCmd = NewRootCmd()
 
+// exitCanceled is the exit code used when the user interrupts the CLI
+const exitCanceled = 130
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	// Cancel the root context on Ctrl-C or SIGTERM so running downloads and
+	// commands stop and clean up; a second signal exits immediately
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		prompt.Warning("Interrupted, cleaning up... (press Ctrl-C again to force quit)")
+		cancel()
+		<-signals
+		os.Exit(exitCanceled)
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	signal.Stop(signals)
+	cancel()
+	prompt.CloseOutput()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, prompt.Redact(err.Error()))
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mineadmin/mine/internal/docker"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/utils"
+	"github.com/mineadmin/mine/internal/validator"
+)
+
+const envAdminPassword = "MINE_ADMIN_PASSWORD"
+
+// seedOptions controls database seeding and the super admin account
+type seedOptions struct {
+	// seed is nil until the user has decided whether to seed
+	seed              *bool
+	adminUsername     string
+	adminEmail        string
+	adminPasswordFile string
+	adminTable        string
+}
+
+// updateAdminScript updates the seeded super admin (id 1) through PDO. All
+// values are passed in the environment so no secret shows up in the process list.
+const updateAdminScript = `
+$driver = getenv('MINE_DB_DRIVER');
+$dsn = $driver === 'pgsql'
+    ? sprintf('pgsql:host=%s;port=%s;dbname=%s', getenv('MINE_DB_HOST'), getenv('MINE_DB_PORT'), getenv('MINE_DB_DATABASE'))
+    : sprintf('mysql:host=%s;port=%s;dbname=%s;charset=utf8mb4', getenv('MINE_DB_HOST'), getenv('MINE_DB_PORT'), getenv('MINE_DB_DATABASE'));
+$pdo = new PDO($dsn, getenv('MINE_DB_USERNAME'), getenv('MINE_DB_PASSWORD'), [PDO::ATTR_ERRMODE => PDO::ERRMODE_EXCEPTION]);
+$stmt = $pdo->prepare('UPDATE ' . getenv('MINE_ADMIN_TABLE') . ' SET username = ?, email = ?, password = ? WHERE id = 1');
+$stmt->execute([getenv('MINE_ADMIN_USERNAME'), getenv('MINE_ADMIN_EMAIL'), password_hash(getenv('MINE_ADMIN_PASSWORD'), PASSWORD_DEFAULT)]);
+if ($stmt->rowCount() === 0) {
+    fwrite(STDERR, "super admin account (id 1) not found\n");
+    exit(1);
+}
+`
+
+func (c *createContext) seedCommand() utils.Command {
+	return c.setupCommand(c.binPhp, "php", []string{"bin/hyperf.php", "db:seed"})
+}
+
+// shouldSeed asks whether to seed unless --seed decided it, remembering the answer for resume
+func (c *createContext) shouldSeed() (bool, error) {
+	if c.state.Seed != nil {
+		return *c.state.Seed, nil
+	}
+	if c.seed.seed == nil {
+		seed, err := prompt.Confirm("Run database seeders and set up the super admin account", true)
+		if err != nil {
+			return false, err
+		}
+		c.seed.seed = &seed
+	}
+	c.state.Seed = c.seed.seed
+	return *c.seed.seed, nil
+}
+
+// seedDatabase runs the project's database seeders
+func (c *createContext) seedDatabase(ctx context.Context) error {
+	seed, err := c.shouldSeed()
+	if err != nil {
+		return err
+	}
+	if !seed {
+		prompt.Info("Skipping database seeders")
+		return nil
+	}
+
+	prompt.Info("Running database seeders...")
+	if err := utils.RunCommand(ctx, c.seedCommand()); err != nil {
+		return fmt.Errorf("database seeding failed: %v", err)
+	}
+	return nil
+}
+
+// setupAdmin sets the seeded super admin's username, email and password
+func (c *createContext) setupAdmin(ctx context.Context) error {
+	if c.state.Seed == nil || !*c.state.Seed {
+		prompt.Info("Database was not seeded, skipping super admin setup")
+		return nil
+	}
+
+	env, err := utils.ReadEnvFile(filepath.Join(c.projectRoot, ".env"))
+	if err != nil {
+		return fmt.Errorf("failed to read .env: %v", err)
+	}
+	prompt.RegisterPassword(env["DB_PASSWORD"])
+
+	prompt.Info("Super Admin Account")
+	username, err := askValue(c.seed.adminUsername, "Super admin username", "admin", validator.NotEmpty)
+	if err != nil {
+		return fmt.Errorf("input failed: %v", err)
+	}
+	email, err := askValue(c.seed.adminEmail, "Super admin email", "admin@example.com", validator.Email)
+	if err != nil {
+		return fmt.Errorf("input failed: %v", err)
+	}
+	password, err := resolveSecret(c.seed.adminPasswordFile, envAdminPassword, "Super admin password (leave empty to generate one)")
+	if err != nil {
+		return fmt.Errorf("input failed: %v", err)
+	}
+	generated := password == ""
+	if generated {
+		if password, err = utils.GeneratePassword(); err != nil {
+			return fmt.Errorf("failed to generate password: %v", err)
+		}
+	}
+	prompt.RegisterPassword(password)
+
+	// Inside the app container the database is reached through its service
+	dbHost, dbPort := env["DB_HOST"], env["DB_PORT"]
+	if c.docker.setup {
+		dbHost, dbPort = docker.ServiceDB, docker.DBPort(env["DB_DRIVER"])
+	}
+	cmd := c.setupCommand(c.binPhp, "php", []string{"-r", updateAdminScript},
+		"MINE_DB_DRIVER="+env["DB_DRIVER"],
+		"MINE_DB_HOST="+dbHost,
+		"MINE_DB_PORT="+dbPort,
+		"MINE_DB_DATABASE="+env["DB_DATABASE"],
+		"MINE_DB_USERNAME="+env["DB_USERNAME"],
+		"MINE_DB_PASSWORD="+env["DB_PASSWORD"],
+		"MINE_ADMIN_TABLE="+quoteIdentifier(env["DB_DRIVER"], env["DB_PREFIX"]+c.seed.adminTable),
+		"MINE_ADMIN_USERNAME="+username,
+		"MINE_ADMIN_EMAIL="+email,
+		"MINE_ADMIN_PASSWORD="+password,
+	)
+	spinner := prompt.StartSpinner("Updating super admin account...")
+	if _, err := utils.Runner().Output(ctx, cmd); err != nil {
+		spinner.Fail()
+		return fmt.Errorf("failed to update super admin account: %v", err)
+	}
+	spinner.Done()
+
+	prompt.Success(fmt.Sprintf("Super admin account: %s <%s>", username, email))
+	if generated {
+		// Printed directly since the output helpers redact it
+		fmt.Printf("\n    Generated password: %s\n    It is shown only once, store it now.\n\n", password)
+	}
+	return nil
+}
+
+// quoteIdentifier quotes a table name for the database driver
+func quoteIdentifier(driver, name string) string {
+	if driver == "pgsql" {
+		return `"` + name + `"`
+	}
+	return "`" + name + "`"
+}
+
+func (c *createContext) planSeed(ctx context.Context) error {
+	if c.seed.seed != nil && !*c.seed.seed {
+		printPlan("skip, not selected")
+		return nil
+	}
+	printCommandPlan(c.seedCommand())
+	return nil
+}
+
+func (c *createContext) planAdmin(ctx context.Context) error {
+	if c.seed.seed != nil && !*c.seed.seed {
+		printPlan("skip, the database is not seeded")
+		return nil
+	}
+	printPlan(
+		fmt.Sprintf("ask for the super admin username, email and password (or read %s)", envAdminPassword),
+		fmt.Sprintf("update the super admin (id 1) in the %s table with %s, generating a password if left empty", c.seed.adminTable, c.setupCommand(c.binPhp, "php", []string{"-r"})),
+	)
+	return nil
+}
@@ -1,68 +1,17 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"text/tabwriter"
 
+	"github.com/fatih/color"
 	"github.com/mineadmin/mine/internal/downloader"
+	"github.com/mineadmin/mine/internal/registry"
 	"github.com/spf13/cobra"
 )
 
-// VersionQuerier 定义版本查询接口
-type VersionQuerier interface {
-	ListVersions() ([]string, error)
-}
-
-// DownloaderQuerier 实现原下载器的版本查询
-type DownloaderQuerier struct {
-	language string
-}
-
-func (q *DownloaderQuerier) ListVersions() ([]string, error) {
-	dl := downloader.NewDownloader(q.language, "", "")
-	return dl.ListVersions()
-}
-
-// GitHubQuerier 实现GitHub API的版本查询
-type GitHubQuerier struct {
-	repo string
-}
-
-func (q *GitHubQuerier) ListVersions() ([]string, error) {
-	resp, err := http.Get("https://api.github.com/repos/" + q.repo + "/releases")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var releases []struct {
-		TagName string `json:"tag_name"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return nil, err
-	}
-
-	var versions []string
-	for _, r := range releases {
-		versions = append(versions, r.TagName)
-	}
-	return versions, nil
-}
-
-// NewVersionQuerier 工厂方法创建版本查询器
-func NewVersionQuerier(language string) VersionQuerier {
-	switch language {
-	case "php":
-		return &GitHubQuerier{repo: "mineadmin/mineadmin"}
-	default:
-		return &DownloaderQuerier{language: language}
-	}
-}
-
 // NewSelectVersionsCmd creates and returns the select-versions command
 func NewSelectVersionsCmd() *cobra.Command {
 	var language string
@@ -74,8 +23,14 @@ func NewSelectVersionsCmd() *cobra.Command {
 Example:
   mine select-versions --language=php`,
 		Run: func(cmd *cobra.Command, args []string) {
-			querier := NewVersionQuerier(language)
-			versions, err := querier.ListVersions()
+			if _, err := registry.GetLanguage(language); err != nil {
+				log.Fatal(err)
+			}
+			src, err := resolveSource(language, "")
+			if err != nil {
+				log.Fatal(err)
+			}
+			versions, err := downloader.NewDownloader(language, "", src).ListVersions(cmd.Context())
 			if err != nil {
 				log.Fatalf("Failed to list versions: %v", err)
 			}
@@ -86,11 +41,11 @@ Example:
 
 			// 使用tabwriter美化输出
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(w, "\033[1mVERSION\tLANGUAGE\tSTATUS\033[0m") // 粗体标题
+			fmt.Fprintln(w, color.New(color.Bold).Sprint("VERSION\tLANGUAGE\tSTATUS")) // 粗体标题
 
 			for _, v := range versions {
 				// 使用彩色输出
-				status := "\033[32mavailable\033[0m" // 绿色的"available"
+				status := color.GreenString("available") // 绿色的"available"
 				fmt.Fprintf(w, "%s\t%s\t%s\n", v, language, status)
 			}
 			w.Flush()
@@ -100,6 +55,7 @@ Example:
 
 	cmd.Flags().StringVarP(&language, "language", "l", "", "Programming language (required)")
 	cmd.MarkFlagRequired("language")
+	cmd.RegisterFlagCompletionFunc("language", completeLanguages)
 
 	return cmd
 }
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/mineadmin/mine/internal/config"
+	"github.com/mineadmin/mine/internal/downloader"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/source"
+)
+
+// resolveSource returns where a language's projects are downloaded from:
+// the source configured for the language, else the project's GitHub
+// repository. A non-empty repo replaces the repository of either.
+func resolveSource(language, repo string) (source.Source, error) {
+	def, err := downloader.DefinitionFor(language)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	srcCfg := cfg.Sources[language]
+	if repo != "" {
+		srcCfg.Repo = repo
+	}
+	prompt.RegisterSecret(srcCfg.ResolveToken())
+	return source.New(srcCfg, def.Repo)
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/composer"
+	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/template"
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// templateOptions selects a project template layered over the download
+type templateOptions struct {
+	location string
+	// vars answers template prompts ahead of time, as given by --template-var
+	vars map[string]string
+
+	loaded  *template.Template
+	cleanup func()
+}
+
+// parseTemplateVars turns KEY=VALUE flags into a map
+func parseTemplateVars(values []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, value := range values {
+		key, v, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --template-var value %q, expected KEY=VALUE", value)
+		}
+		vars[key] = v
+	}
+	return vars, nil
+}
+
+// resolveTemplateLocation checks a --template value and makes local paths
+// absolute so a resumed run finds them from any directory
+func resolveTemplateLocation(location string) (string, error) {
+	kind, err := template.Kind(location)
+	if err != nil {
+		return "", err
+	}
+	if kind == template.KindGit || strings.Contains(location, "://") {
+		return location, nil
+	}
+	return filepath.Abs(location)
+}
+
+// loadTemplate fetches the selected template once per run
+func (c *createContext) loadTemplate(ctx context.Context) (*template.Template, error) {
+	if c.template.loaded != nil {
+		return c.template.loaded, nil
+	}
+	spinner := prompt.StartSpinner(fmt.Sprintf("Fetching template %s...", c.template.location))
+	t, cleanup, err := template.Fetch(ctx, c.template.location)
+	if err != nil {
+		spinner.Fail()
+		return nil, err
+	}
+	spinner.Done()
+	c.template.loaded, c.template.cleanup = t, cleanup
+	return t, nil
+}
+
+// closeTemplate removes a template downloaded by loadTemplate
+func (c *createContext) closeTemplate() {
+	if c.template.cleanup != nil {
+		c.template.cleanup()
+	}
+}
+
+// askTemplateVars answers the template's prompts, using --template-var
+// values where given. Answers are saved to the state so post steps and
+// resumed runs see the same values.
+func (c *createContext) askTemplateVars(t *template.Template) error {
+	vars := map[string]string{}
+	for key, value := range c.template.vars {
+		vars[key] = value
+	}
+	if len(t.Manifest.Prompts) > 0 {
+		prompt.Info(fmt.Sprintf("Template %s Configuration", t.Title()))
+	}
+	for _, p := range t.Manifest.Prompts {
+		if _, ok := vars[p.Name]; ok {
+			continue
+		}
+		label := p.Label
+		if label == "" {
+			label = p.Name
+		}
+
+		var (
+			value string
+			err   error
+		)
+		if len(p.Options) > 0 {
+			_, value, err = prompt.Select(label, p.Options)
+		} else {
+			value, err = prompt.Input(label, p.Default)
+		}
+		if err != nil {
+			return fmt.Errorf("input failed: %v", err)
+		}
+		vars[p.Name] = value
+	}
+	c.template.vars = vars
+	c.state.TemplateVars = vars
+	return nil
+}
+
+// templateData collects the values templates can refer to. Settings are
+// read back from .env so a resumed run renders the same values.
+func (c *createContext) templateData() (template.Data, error) {
+	data := template.Data{
+		ProjectName: filepath.Base(filepath.Clean(c.projectRoot)),
+		Language:    c.language,
+		Version:     c.version,
+		Platform:    c.platform,
+		BinPhp:      c.binPhp,
+		BinComposer: c.binComposer,
+		Vars:        c.template.vars,
+	}
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+
+	env, err := utils.ReadEnvFile(filepath.Join(c.projectRoot, ".env"))
+	if os.IsNotExist(err) {
+		return data, nil
+	}
+	if err != nil {
+		return data, fmt.Errorf("failed to read .env: %v", err)
+	}
+	data.DBDriver, data.DBHost, data.DBPort = env["DB_DRIVER"], env["DB_HOST"], env["DB_PORT"]
+	data.DBName, data.DBUser = env["DB_DATABASE"], env["DB_USERNAME"]
+	data.RedisHost, data.RedisPort, data.RedisDB = env["REDIS_HOST"], env["REDIS_PORT"], env["REDIS_DB"]
+	data.AppURL = env["APP_URL"]
+	if u, err := url.Parse(data.AppURL); err == nil {
+		data.AppHost, data.AppPort = u.Hostname(), u.Port()
+	}
+	return data, nil
+}
+
+// applyTemplate lays the template over the project and applies its
+// composer packages and .env keys
+func (c *createContext) applyTemplate(ctx context.Context) error {
+	t, err := c.loadTemplate(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.askTemplateVars(t); err != nil {
+		return err
+	}
+	data, err := c.templateData()
+	if err != nil {
+		return err
+	}
+
+	spinner := prompt.StartSpinner(fmt.Sprintf("Applying template %s...", t.Title()))
+	files, err := t.Apply(c.projectRoot, data)
+	if err != nil {
+		spinner.Fail()
+		return fmt.Errorf("failed to apply template: %v", err)
+	}
+	if changes := t.PackageChanges(); len(changes) > 0 {
+		_, err := editComposerJSON(c.projectRoot, func(f *composer.File) error {
+			return composer.ApplyPackageChanges(f, changes)
+		})
+		if err != nil {
+			spinner.Fail()
+			return fmt.Errorf("failed to update composer.json: %v", err)
+		}
+	}
+	if keys := t.EnvKeys(); len(keys) > 0 {
+		values := map[string]string{}
+		for _, key := range keys {
+			if values[key], err = template.Render("env "+key, t.Manifest.Env[key], data); err != nil {
+				spinner.Fail()
+				return err
+			}
+		}
+		if err := utils.SetEnvValues(filepath.Join(c.projectRoot, ".env"), values, keys); err != nil {
+			spinner.Fail()
+			return fmt.Errorf("failed to update .env: %v", err)
+		}
+	}
+	spinner.Stop()
+	prompt.Success(fmt.Sprintf("Template %s applied (%d files)", t.Title(), len(files)))
+	return nil
+}
+
+// templatePostCommands renders the template's post steps into commands
+func (c *createContext) templatePostCommands(t *template.Template) ([]utils.Command, error) {
+	data, err := c.templateData()
+	if err != nil {
+		return nil, err
+	}
+	var cmds []utils.Command
+	for i, step := range t.Manifest.PostSteps {
+		args := make([]string, len(step.Run))
+		for j, arg := range step.Run {
+			if args[j], err = template.Render(fmt.Sprintf("post step %d", i+1), arg, data); err != nil {
+				return nil, err
+			}
+		}
+		cmds = append(cmds, c.command(args[0], args[1:]))
+	}
+	return cmds, nil
+}
+
+// runTemplatePostSteps runs the template's post steps in the finished project
+func (c *createContext) runTemplatePostSteps(ctx context.Context) error {
+	t, err := c.loadTemplate(ctx)
+	if err != nil {
+		return err
+	}
+	cmds, err := c.templatePostCommands(t)
+	if err != nil {
+		return err
+	}
+	for _, cmd := range cmds {
+		if !utils.CheckCommandExists(cmd.Name) {
+			prompt.Info(fmt.Sprintf("Install %s and resume, or run manually in %s: %s", cmd.Name, c.projectRoot, cmd))
+			return fmt.Errorf("command '%s' not found", cmd.Name)
+		}
+		prompt.Info(fmt.Sprintf("Running %s...", cmd))
+		if err := utils.RunCommand(ctx, cmd); err != nil {
+			return fmt.Errorf("%s failed: %v", cmd, err)
+		}
+	}
+	return nil
+}
+
+// planLocalTemplate loads the template for a dry run when it is a local
+// directory. Git and zip templates would have to be fetched or extracted,
+// so nil is returned for them.
+func (c *createContext) planLocalTemplate(ctx context.Context) (*template.Template, error) {
+	kind, err := template.Kind(c.template.location)
+	if err != nil {
+		return nil, err
+	}
+	if kind != template.KindDir {
+		return nil, nil
+	}
+	return c.loadTemplate(ctx)
+}
+
+func (c *createContext) planTemplate(ctx context.Context) error {
+	t, err := c.planLocalTemplate(ctx)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		printPlan(fmt.Sprintf("apply template from %s", c.template.location))
+		return nil
+	}
+	files, err := t.Files()
+	if err != nil {
+		return err
+	}
+
+	printPlan(fmt.Sprintf("apply template %s from %s", t.Title(), c.template.location))
+	for _, p := range t.Manifest.Prompts {
+		if value, ok := c.template.vars[p.Name]; ok {
+			printPlan(fmt.Sprintf("use %s=%s", p.Name, value))
+		} else {
+			printPlan(fmt.Sprintf("ask for %s", p.Name))
+		}
+	}
+	for _, file := range files {
+		target := filepath.Join(c.projectRoot, filepath.FromSlash(template.TargetPath(file)))
+		if strings.HasSuffix(file, template.RenderSuffix) {
+			printPlan(fmt.Sprintf("render %s to %s", file, target))
+		} else {
+			printPlan(fmt.Sprintf("copy %s to %s", file, target))
+		}
+	}
+	for _, change := range t.PackageChanges() {
+		printPlan(fmt.Sprintf("composer.json: %s", change))
+	}
+	for _, key := range t.EnvKeys() {
+		printPlan(fmt.Sprintf(".env: set %s=%s", key, t.Manifest.Env[key]))
+	}
+	return nil
+}
+
+func (c *createContext) planTemplatePostSteps(ctx context.Context) error {
+	t, err := c.planLocalTemplate(ctx)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		printPlan(fmt.Sprintf("run the post steps of the template from %s", c.template.location))
+		return nil
+	}
+	// Prompts are not answered during a dry run, so show the commands unrendered
+	for _, step := range t.Manifest.PostSteps {
+		cmd := c.command(step.Run[0], step.Run[1:])
+		if step.Name != "" {
+			printPlan(step.Name + ":")
+		}
+		printCommandPlan(cmd)
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package composer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// defaultIndent is used when the document has no indented member to copy
+const defaultIndent = "    "
+
+// File is a composer.json being edited. Edits splice the original text, so
+// key order, indentation, escaping and the trailing newline of everything
+// that is not edited stay exactly as they were.
+type File struct {
+	path    string
+	data    []byte
+	root    *node
+	changes []Change
+}
+
+// Change records a single edit to the document
+type Change struct {
+	Path []string
+	// Old and New are compact JSON; Old is nil for additions, New for removals
+	Old, New json.RawMessage
+}
+
+// String describes the change for humans
+func (c Change) String() string {
+	path := strings.Join(c.Path, ".")
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("add %s: %s", path, c.New)
+	case c.New == nil:
+		return fmt.Sprintf("remove %s", path)
+	default:
+		return fmt.Sprintf("change %s: %s -> %s", path, c.Old, c.New)
+	}
+}
+
+// Load reads a composer.json for editing
+func Load(path string) (*File, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	f.path = path
+	return f, nil
+}
+
+// Parse reads a composer.json document from memory
+func Parse(data []byte) (*File, error) {
+	root, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.kind != '{' {
+		return nil, fmt.Errorf("composer.json must contain an object")
+	}
+	return &File{data: data, root: root}, nil
+}
+
+// Bytes returns the current document
+func (f *File) Bytes() []byte {
+	return f.data
+}
+
+// Changes returns the edits made since the file was loaded
+func (f *File) Changes() []Change {
+	return f.changes
+}
+
+// Save writes the document back if it was changed
+func (f *File) Save() error {
+	if len(f.changes) == 0 {
+		return nil
+	}
+	return ioutil.WriteFile(f.path, f.data, 0644)
+}
+
+// Get decodes the value at path into v and reports whether it exists
+func (f *File) Get(path []string, v interface{}) (bool, error) {
+	n, _, _ := f.lookup(path)
+	if n == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(f.data[n.start:n.end], v)
+}
+
+// Has reports whether path exists
+func (f *File) Has(path ...string) bool {
+	n, _, _ := f.lookup(path)
+	return n != nil
+}
+
+// lookup walks path and returns the node found there. When it does not
+// exist, parent is the deepest object on the path and depth how many keys matched.
+func (f *File) lookup(path []string) (n *node, parent *node, depth int) {
+	n = f.root
+	for i, key := range path {
+		if n.kind != '{' {
+			return nil, nil, i
+		}
+		idx := n.find(key)
+		if idx < 0 {
+			return nil, n, i
+		}
+		parent, n = n, n.members[idx].value
+	}
+	return n, parent, len(path)
+}
+
+// Set sets the value at path, creating missing parent objects. Existing keys
+// keep their position, new keys are appended to their object.
+func (f *File) Set(path []string, value interface{}) error {
+	if len(path) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	newValue, err := compact(value)
+	if err != nil {
+		return err
+	}
+
+	n, parent, depth := f.lookup(path)
+	if n != nil {
+		old := compactRaw(f.data[n.start:n.end])
+		if bytes.Equal(old, newValue) {
+			return nil
+		}
+		encoded, err := f.encode(value, f.lineIndent(n.start))
+		if err != nil {
+			return err
+		}
+		f.changes = append(f.changes, Change{Path: path, Old: old, New: newValue})
+		return f.splice(n.start, n.end, encoded)
+	}
+	if parent == nil {
+		return fmt.Errorf("%s is not an object", strings.Join(path[:depth], "."))
+	}
+
+	// Wrap the value in the objects missing below parent
+	for i := len(path) - 1; i > depth; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+	if err := f.insertMember(parent, path[depth], value); err != nil {
+		return err
+	}
+	f.changes = append(f.changes, Change{Path: path, New: newValue})
+	return nil
+}
+
+// Delete removes the value at path and reports whether it existed
+func (f *File) Delete(path ...string) (bool, error) {
+	if len(path) == 0 {
+		return false, fmt.Errorf("empty path")
+	}
+	n, parent, _ := f.lookup(path)
+	if n == nil {
+		return false, nil
+	}
+	idx := parent.find(path[len(path)-1])
+	old := compactRaw(f.data[n.start:n.end])
+
+	var err error
+	switch {
+	case len(parent.members) == 1:
+		err = f.splice(parent.start+1, parent.end-1, nil)
+	case idx > 0:
+		// Drop the separator before the member along with it
+		err = f.splice(parent.members[idx-1].value.end, n.end, nil)
+	default:
+		err = f.splice(parent.members[0].keyStart, parent.members[1].keyStart, nil)
+	}
+	if err != nil {
+		return false, err
+	}
+	f.changes = append(f.changes, Change{Path: path, Old: old})
+	return true, nil
+}
+
+// Append adds values to the end of the array at path, creating it if missing
+func (f *File) Append(path []string, values ...interface{}) error {
+	n, _, _ := f.lookup(path)
+	if n == nil {
+		return f.Set(path, values)
+	}
+	if n.kind != '[' {
+		return fmt.Errorf("%s is not an array", strings.Join(path, "."))
+	}
+	for _, value := range values {
+		n, _, _ = f.lookup(path)
+		var last *node
+		if len(n.items) > 0 {
+			last = n.items[len(n.items)-1]
+		}
+		if err := f.insertEntry(n, last, "", value); err != nil {
+			return err
+		}
+		newValue, err := compact(value)
+		if err != nil {
+			return err
+		}
+		f.changes = append(f.changes, Change{Path: path, New: newValue})
+	}
+	return nil
+}
+
+// insertMember appends key to the object obj
+func (f *File) insertMember(obj *node, key string, value interface{}) error {
+	var last *node
+	if len(obj.members) > 0 {
+		last = obj.members[len(obj.members)-1].value
+	}
+	name, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return f.insertEntry(obj, last, string(name)+": ", value)
+}
+
+// insertEntry writes prefix+value after last, the final entry of container,
+// or as the only entry when last is nil. Entries are laid out like their
+// siblings: on their own line when the container spans lines, inline otherwise.
+// An empty container follows the layout of the one enclosing it.
+func (f *File) insertEntry(container, last *node, prefix string, value interface{}) error {
+	newline := f.newline()
+	if last == nil {
+		if outer := f.root.enclosing(container); outer != nil && f.inline(outer) {
+			encoded, err := compact(value)
+			if err != nil {
+				return err
+			}
+			return f.splice(container.start+1, container.end-1, []byte(prefix+string(encoded)))
+		}
+		indent := f.lineIndent(container.start) + f.indentUnit()
+		encoded, err := f.encode(value, indent)
+		if err != nil {
+			return err
+		}
+		text := newline + indent + prefix + string(encoded) + newline + f.lineIndent(container.start)
+		return f.splice(container.start+1, container.end-1, []byte(text))
+	}
+
+	if f.inline(container) {
+		encoded, err := compact(value)
+		if err != nil {
+			return err
+		}
+		return f.splice(last.end, last.end, []byte(", "+prefix+string(encoded)))
+	}
+
+	indent := f.lineIndent(container.firstStart())
+	encoded, err := f.encode(value, indent)
+	if err != nil {
+		return err
+	}
+	return f.splice(last.end, last.end, []byte(","+newline+indent+prefix+string(encoded)))
+}
+
+// inline reports whether the entries of the non-empty container share its opening line
+func (f *File) inline(container *node) bool {
+	return !bytes.Contains(f.data[container.start:container.firstStart()], []byte("\n"))
+}
+
+// splice replaces data[start:end] and re-parses the document
+func (f *File) splice(start, end int, text []byte) error {
+	data := make([]byte, 0, len(f.data)-(end-start)+len(text))
+	data = append(data, f.data[:start]...)
+	data = append(data, text...)
+	data = append(data, f.data[end:]...)
+	root, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("edit produced invalid JSON: %v", err)
+	}
+	f.data, f.root = data, root
+	return nil
+}
+
+// encode marshals value indented to continue a line starting with prefix.
+// Unlike json.Marshal it leaves <, > and & alone, as composer does.
+func (f *File) encode(value interface{}, prefix string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(prefix, f.indentUnit())
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	out := bytes.TrimRight(buf.Bytes(), "\n")
+	if f.newline() != "\n" {
+		out = bytes.ReplaceAll(out, []byte("\n"), []byte(f.newline()))
+	}
+	return out, nil
+}
+
+// lineIndent returns the whitespace starting the line that contains offset
+func (f *File) lineIndent(offset int) string {
+	lineStart := bytes.LastIndexByte(f.data[:offset], '\n') + 1
+	end := lineStart
+	for end < len(f.data) && (f.data[end] == ' ' || f.data[end] == '\t') {
+		end++
+	}
+	return string(f.data[lineStart:end])
+}
+
+// indentUnit returns the indentation of the document's first nested line
+func (f *File) indentUnit() string {
+	if len(f.root.members) > 0 {
+		if indent := f.lineIndent(f.root.members[0].keyStart); indent != "" {
+			return indent
+		}
+	}
+	return defaultIndent
+}
+
+func (f *File) newline() string {
+	if bytes.Contains(f.data, []byte("\r\n")) {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// compact encodes value as compact JSON without HTML escaping
+func compact(value interface{}) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return compactRaw(buf.Bytes()), nil
+}
+
+func compactRaw(data []byte) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return append(json.RawMessage(nil), data...)
+	}
+	return buf.Bytes()
+}
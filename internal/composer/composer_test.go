@@ -0,0 +1,216 @@
+package composer
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `{
+    "name": "mineadmin/mineadmin",
+    "require": {
+        "php": ">=8.1",
+        "hyperf/engine": "^2.10",
+        "mineadmin/core": "~3.0"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.0"
+    },
+    "scripts": {
+        "test": "co-phpunit --prepend test/bootstrap.php -c phpunit.xml --colors=always"
+    }
+}
+`
+
+func TestEdits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		edit  func(f *File) error
+		want  string
+	}{
+		{
+			name:  "update keeps key order and layout",
+			input: sample,
+			edit:  func(f *File) error { return f.Require(SectionRequire, "hyperf/engine", "^2.12") },
+			want:  strings.Replace(sample, `"^2.10"`, `"^2.12"`, 1),
+		},
+		{
+			name:  "insert after the last member",
+			input: sample,
+			edit:  func(f *File) error { return f.Require(SectionRequire, "ext-swow", ">=1.4") },
+			want:  strings.Replace(sample, `"~3.0"`, `"~3.0",`+"\n"+`        "ext-swow": ">=1.4"`, 1),
+		},
+		{
+			name:  "constraints are not HTML escaped",
+			input: sample,
+			edit:  func(f *File) error { return f.Require(SectionRequire, "php", ">=8.1 <8.4") },
+			want:  strings.Replace(sample, `">=8.1"`, `">=8.1 <8.4"`, 1),
+		},
+		{
+			name:  "delete a middle member",
+			input: sample,
+			edit:  func(f *File) error { _, err := f.Remove(SectionRequire, "hyperf/engine"); return err },
+			want:  strings.Replace(sample, `        "hyperf/engine": "^2.10",`+"\n", "", 1),
+		},
+		{
+			name:  "delete the first member",
+			input: sample,
+			edit:  func(f *File) error { _, err := f.Remove(SectionRequire, "php"); return err },
+			want:  strings.Replace(sample, `        "php": ">=8.1",`+"\n", "", 1),
+		},
+		{
+			name:  "delete the last member",
+			input: sample,
+			edit:  func(f *File) error { _, err := f.Remove(SectionRequire, "mineadmin/core"); return err },
+			want:  strings.Replace(sample, `"^2.10",`+"\n"+`        "mineadmin/core": "~3.0"`, `"^2.10"`, 1),
+		},
+		{
+			name:  "emptied object is refilled with the parent's indentation",
+			input: sample,
+			edit: func(f *File) error {
+				if _, err := f.Remove(SectionRequireDev, "phpunit/phpunit"); err != nil {
+					return err
+				}
+				if err := f.Require(SectionRequireDev, "mockery/mockery", "^1.6"); err != nil {
+					return err
+				}
+				return f.Require(SectionRequireDev, "phpstan/phpstan", "^1.10")
+			},
+			want: strings.Replace(sample, `        "phpunit/phpunit": "^10.0"`,
+				`        "mockery/mockery": "^1.6",`+"\n"+`        "phpstan/phpstan": "^1.10"`, 1),
+		},
+		{
+			name:  "emptied object in an inline document stays inline",
+			input: `{"require":{"a":"1"}}`,
+			edit: func(f *File) error {
+				if _, err := f.Remove(SectionRequire, "a"); err != nil {
+					return err
+				}
+				if err := f.Require(SectionRequire, "b", "1"); err != nil {
+					return err
+				}
+				return f.Require(SectionRequire, "c", "1")
+			},
+			want: `{"require":{"b": "1", "c": "1"}}`,
+		},
+		{
+			name:  "missing section is created",
+			input: "{\n    \"name\": \"m\"\n}\n",
+			edit:  func(f *File) error { return f.Require(SectionRequireDev, "phpunit/phpunit", "^10.0") },
+			want:  "{\n    \"name\": \"m\",\n    \"require-dev\": {\n        \"phpunit/phpunit\": \"^10.0\"\n    }\n}\n",
+		},
+		{
+			name:  "CRLF line endings are kept",
+			input: "{\r\n  \"require\": {\r\n    \"php\": \">=8.1\"\r\n  }\r\n}\r\n",
+			edit:  func(f *File) error { return f.Require(SectionRequire, "ext-swoole", ">=5.0") },
+			want:  "{\r\n  \"require\": {\r\n    \"php\": \">=8.1\",\r\n    \"ext-swoole\": \">=5.0\"\r\n  }\r\n}\r\n",
+		},
+		{
+			name:  "mirror is appended to repositories arrays",
+			input: "{\n    \"repositories\": [\n        {\"type\": \"path\", \"url\": \"./plugins\"}\n    ]\n}\n",
+			edit:  func(f *File) error { return f.SetMirror("https://mirrors.example.com/composer/") },
+			want: "{\n    \"repositories\": [\n        {\"type\": \"path\", \"url\": \"./plugins\"},\n" +
+				"        {\n            \"type\": \"composer\",\n            \"url\": \"https://mirrors.example.com/composer/\"\n        },\n" +
+				"        {\n            \"packagist.org\": false\n        }\n    ]\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.edit(f); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(f.Bytes()); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChanges(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Require(SectionRequire, "php", ">=8.1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Changes()) != 0 {
+		t.Errorf("setting the same value recorded %v", f.Changes())
+	}
+
+	err = ApplyPackageChanges(f, []PackageChange{
+		{Section: SectionRequire, Package: "hyperf/engine", Version: "^2.12"},
+		{Section: SectionRequire, Package: "ext-swow", Version: ">=1.4"},
+		{Section: SectionRequireDev, Package: "phpunit/phpunit"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range f.Changes() {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`change require.hyperf/engine: "^2.10" -> "^2.12"`,
+		`add require.ext-swow: ">=1.4"`,
+		`remove require-dev.phpunit/phpunit`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	var constraint string
+	if ok, err := f.Get([]string{SectionRequire, "ext-swow"}, &constraint); !ok || err != nil || constraint != ">=1.4" {
+		t.Errorf("Get = %q, %v, %v", constraint, ok, err)
+	}
+	if f.Has(SectionRequireDev, "phpunit/phpunit") {
+		t.Error("removed package is still present")
+	}
+}
+
+func TestSetThroughScalar(t *testing.T) {
+	f, err := Parse([]byte(`{"name": "m"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set([]string{"name", "x"}, "1"); err == nil {
+		t.Error("setting below a string should fail")
+	}
+}
+
+func TestSetMirrorTwice(t *testing.T) {
+	const url = "https://mirrors.example.com/composer/"
+	tests := map[string]string{
+		"object":                            "{\n    \"name\": \"m\"\n}\n",
+		"array":                             "{\n    \"repositories\": [\n        {\"type\": \"path\", \"url\": \"./plugins\"}\n    ]\n}\n",
+		"array already disabling packagist": "{\n    \"repositories\": [\n        {\"packagist.org\": false}\n    ]\n}\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := Parse([]byte(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := f.SetMirror(url); err != nil {
+				t.Fatal(err)
+			}
+			once := string(f.Bytes())
+			if err := f.SetMirror(url); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(f.Bytes()); got != once {
+				t.Errorf("second SetMirror changed the file:\n%s\nafter the first:\n%s", got, once)
+			}
+			if n := strings.Count(once, url); n != 1 {
+				t.Errorf("mirror listed %d times:\n%s", n, once)
+			}
+			if n := strings.Count(once, `"packagist.org": false`); name != "object" && n != 1 {
+				t.Errorf("packagist.org disabled %d times:\n%s", n, once)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// node is a parsed JSON value together with its location in the source, so
+// edits can splice the text instead of re-encoding the whole document
+type node struct {
+	kind       byte // '{', '[' or 0 for scalars
+	start, end int  // byte range of the value
+	members    []member
+	items      []*node
+}
+
+// member is a key/value pair of an object
+type member struct {
+	key      string
+	keyStart int
+	value    *node
+}
+
+// find returns the index of the member named key, or -1
+func (n *node) find(key string) int {
+	for i, m := range n.members {
+		if m.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// firstStart returns the offset of the first entry of a non-empty container
+func (n *node) firstStart() int {
+	if n.kind == '{' {
+		return n.members[0].keyStart
+	}
+	return n.items[0].start
+}
+
+// enclosing returns the container holding target directly, or nil when
+// target is n itself or not below it
+func (n *node) enclosing(target *node) *node {
+	children := append([]*node{}, n.items...)
+	for _, m := range n.members {
+		children = append(children, m.value)
+	}
+	for _, child := range children {
+		if child == target {
+			return n
+		}
+		if outer := child.enclosing(target); outer != nil {
+			return outer
+		}
+	}
+	return nil
+}
+
+// parser builds a node tree from a document already checked with json.Valid
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func parse(data []byte) (*node, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JSON")
+	}
+	p := &parser{data: data}
+	return p.value()
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) value() (*node, error) {
+	p.skipSpace()
+	n := &node{start: p.pos}
+	switch p.data[p.pos] {
+	case '{':
+		n.kind = '{'
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.data[p.pos] == '}' {
+				p.pos++
+				break
+			}
+			if p.data[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			keyStart := p.pos
+			p.skipString()
+			var key string
+			if err := json.Unmarshal(p.data[keyStart:p.pos], &key); err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			p.pos++ // colon
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.members = append(n.members, member{key: key, keyStart: keyStart, value: value})
+		}
+	case '[':
+		n.kind = '['
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.data[p.pos] == ']' {
+				p.pos++
+				break
+			}
+			if p.data[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case '"':
+		p.skipString()
+	default:
+		for p.pos < len(p.data) {
+			c := p.data[p.pos]
+			if c == ',' || c == '}' || c == ']' || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+				break
+			}
+			p.pos++
+		}
+	}
+	n.end = p.pos
+	return n, nil
+}
+
+func (p *parser) skipString() {
+	p.pos++ // opening quote
+	for p.data[p.pos] != '"' {
+		if p.data[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	p.pos++
+}
@@ -0,0 +1,97 @@
+package composer
+
+import "fmt"
+
+// Sections of composer.json the helpers below edit
+const (
+	SectionRequire    = "require"
+	SectionRequireDev = "require-dev"
+)
+
+// PackageChange describes a single edit to a composer.json package section
+type PackageChange struct {
+	Section string
+	Package string
+	// Version is the constraint to set; empty removes the package
+	Version string
+}
+
+// String describes the change for humans
+func (c PackageChange) String() string {
+	if c.Version == "" {
+		return fmt.Sprintf("remove %s.%s", c.Section, c.Package)
+	}
+	return fmt.Sprintf("set %s.%s to %q", c.Section, c.Package, c.Version)
+}
+
+// Apply makes the change to f
+func (c PackageChange) Apply(f *File) error {
+	if c.Version == "" {
+		_, err := f.Delete(c.Section, c.Package)
+		return err
+	}
+	return f.Set([]string{c.Section, c.Package}, c.Version)
+}
+
+// ApplyPackageChanges applies changes to f in order
+func ApplyPackageChanges(f *File, changes []PackageChange) error {
+	for _, change := range changes {
+		if err := change.Apply(f); err != nil {
+			return fmt.Errorf("failed to %s: %v", change, err)
+		}
+	}
+	return nil
+}
+
+// Require adds or updates a package constraint in section
+func (f *File) Require(section, pkg, version string) error {
+	return f.Set([]string{section, pkg}, version)
+}
+
+// Remove drops a package from section and reports whether it was there
+func (f *File) Remove(section, pkg string) (bool, error) {
+	return f.Delete(section, pkg)
+}
+
+// repository is a composer repository entry, ordered as composer writes it
+type repository struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// SetMirror replaces packagist.org with a composer repository at url, the
+// same shape as `composer config repo.packagist composer <url>`. Projects
+// that list repositories as an array get the mirror appended with
+// packagist.org disabled, unless they already have them.
+func (f *File) SetMirror(url string) error {
+	mirror := repository{Type: "composer", URL: url}
+	n, _, _ := f.lookup([]string{"repositories"})
+	if n == nil || n.kind != '[' {
+		return f.Set([]string{"repositories", "packagist"}, mirror)
+	}
+
+	var entries []map[string]interface{}
+	// Entries that are not objects cannot be a mirror, so decoding errors are ignored
+	f.Get([]string{"repositories"}, &entries)
+	hasMirror, packagistDisabled := false, false
+	for _, entry := range entries {
+		if entry["type"] == mirror.Type && entry["url"] == url {
+			hasMirror = true
+		}
+		if enabled, ok := entry["packagist.org"].(bool); ok && !enabled {
+			packagistDisabled = true
+		}
+	}
+
+	var values []interface{}
+	if !hasMirror {
+		values = append(values, mirror)
+	}
+	if !packagistDisabled {
+		values = append(values, map[string]bool{"packagist.org": false})
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return f.Append([]string{"repositories"}, values...)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mineadmin/mine/internal/source"
+)
+
+// EnvConfigPath overrides the location of the configuration file
+const EnvConfigPath = "MINE_CONFIG"
+
+// Config holds user defaults for the CLI, read from a JSON file
+type Config struct {
+	// ComposerMirror is a mirror preset name or repository URL used by composer install
+	ComposerMirror string `json:"composer_mirror,omitempty"`
+	// ComposerMirrorMode is "temporary" or "permanent"
+	ComposerMirrorMode string `json:"composer_mirror_mode,omitempty"`
+	// Sources overrides where projects are downloaded from, keyed by language
+	Sources map[string]source.Config `json:"sources,omitempty"`
+}
+
+// Path returns the configuration file location: $MINE_CONFIG or
+// <user config dir>/mine/config.json
+func Path() string {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mine", "config.json")
+}
+
+// Load reads the configuration file. A missing file yields an empty configuration.
+func Load() (*Config, error) {
+	cfg := &Config{}
+	path := Path()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// Options describe the deployed project
+type Options struct {
+	// Name identifies the service, upstream and log files
+	Name string
+	// ProjectDir is the absolute project path on the server
+	ProjectDir string
+	// BinPhp is the absolute path of the PHP binary
+	BinPhp string
+	User   string
+	// AppPort is the port the Hyperf server listens on, from APP_URL
+	AppPort    string
+	ServerName string
+	// LogDir receives the server output when the process manager does not log itself
+	LogDir string
+	// FrontendDist is the built admin UI served by nginx, empty to proxy everything
+	FrontendDist string
+	// APIPath is the location nginx proxies to the server when it serves the frontend
+	APIPath string
+	// OpenFiles is the open file limit of the server process
+	OpenFiles int
+}
+
+// Ident returns Name usable in nginx variable names
+func (o Options) Ident() string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(o.Name)
+}
+
+// Target is a kind of deployment file
+type Target struct {
+	Name        string
+	Description string
+	// File is the generated file name, %s standing for Options.Name
+	File string
+	// Install explains how to enable the file, {path} standing for its path
+	// and {name} for Options.Name
+	Install []string
+	text    string
+}
+
+// Render returns the file for opts
+func (t Target) Render(opts Options) (string, error) {
+	tmpl, err := template.New(t.Name).Parse(t.text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// FileName returns the generated file name for the service
+func (t Target) FileName(name string) string {
+	return fmt.Sprintf(t.File, name)
+}
+
+// InstallSteps returns the commands enabling the file at path
+func (t Target) InstallSteps(path, name string) []string {
+	r := strings.NewReplacer("{path}", path, "{name}", name)
+	steps := make([]string, len(t.Install))
+	for i, step := range t.Install {
+		steps[i] = r.Replace(step)
+	}
+	return steps
+}
+
+// Get returns the target called name or an error listing the known ones
+func Get(name string) (Target, error) {
+	for _, t := range targets {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Target{}, utils.UnknownChoice("deploy target", name, Names())
+}
+
+// All returns every target
+func All() []Target {
+	return targets
+}
+
+// Names returns the target names, sorted
+func Names() []string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,139 @@
+package deploy
+
+// targets are the supported deployment files; add one by adding an entry here
+var targets = []Target{
+	{
+		Name:        "systemd",
+		Description: "systemd unit running the Hyperf server",
+		File:        "%s.service",
+		Install: []string{
+			"sudo cp {path} /etc/systemd/system/{name}.service",
+			"sudo systemctl daemon-reload",
+			"sudo systemctl enable --now {name}",
+			"journalctl -u {name} -f",
+		},
+		text: systemdTemplate,
+	},
+	{
+		Name:        "supervisor",
+		Description: "supervisor program running the Hyperf server",
+		File:        "%s.conf",
+		Install: []string{
+			"sudo cp {path} /etc/supervisor/conf.d/{name}.conf",
+			"sudo supervisorctl reread && sudo supervisorctl update",
+			"sudo supervisorctl status {name}",
+		},
+		text: supervisorTemplate,
+	},
+	{
+		Name:        "nginx",
+		Description: "nginx reverse proxy for the server and the built admin UI",
+		File:        "%s.nginx.conf",
+		Install: []string{
+			"sudo cp {path} /etc/nginx/conf.d/{name}.conf",
+			"sudo nginx -t && sudo systemctl reload nginx",
+		},
+		text: nginxTemplate,
+	},
+}
+
+const systemdTemplate = `# Generated by mine
+[Unit]
+Description={{.Name}} (MineAdmin Hyperf server)
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+User={{.User}}
+WorkingDirectory={{.ProjectDir}}
+ExecStart={{.BinPhp}} {{.ProjectDir}}/bin/hyperf.php start
+Restart=always
+RestartSec=5
+# Give running requests time to finish on stop
+KillSignal=SIGTERM
+TimeoutStopSec=30
+LimitNOFILE={{.OpenFiles}}
+StandardOutput=journal
+StandardError=journal
+SyslogIdentifier={{.Name}}
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const supervisorTemplate = `; Generated by mine
+; supervisor sets no per-program open file limit; raise it for all programs
+; with minfds={{.OpenFiles}} in the [supervisord] section.
+[program:{{.Name}}]
+directory={{.ProjectDir}}
+command={{.BinPhp}} {{.ProjectDir}}/bin/hyperf.php start
+user={{.User}}
+autostart=true
+autorestart=true
+startsecs=5
+startretries=3
+stopsignal=TERM
+stopwaitsecs=30
+stopasgroup=true
+killasgroup=true
+redirect_stderr=true
+stdout_logfile={{.LogDir}}/{{.Name}}.log
+stdout_logfile_maxbytes=50MB
+stdout_logfile_backups=10
+`
+
+const nginxTemplate = `# Generated by mine
+upstream {{.Name}} {
+    server 127.0.0.1:{{.AppPort}};
+    keepalive 32;
+}
+
+map $http_upgrade $connection_upgrade_{{.Ident}} {
+    default upgrade;
+    ''      '';
+}
+
+server {
+    listen 80;
+    server_name {{.ServerName}};
+
+    access_log /var/log/nginx/{{.Name}}.access.log;
+    error_log /var/log/nginx/{{.Name}}.error.log;
+
+    client_max_body_size 20m;
+{{- if .FrontendDist}}
+
+    # The admin UI, built in web/
+    root {{.FrontendDist}};
+    index index.html;
+
+    location / {
+        try_files $uri $uri/ /index.html;
+    }
+
+    location ~* \.(js|css|png|jpg|jpeg|gif|svg|ico|woff2?)$ {
+        expires 30d;
+        access_log off;
+    }
+
+    # The frontend must be built with VITE_APP_API_BASEURL={{.APIPath}}
+    # ^~ keeps the asset regex above from serving API URLs such as {{.APIPath}}/avatar.png
+    location ^~ {{.APIPath}}/ {
+        proxy_pass http://{{.Name}}/;
+{{- else}}
+
+    location / {
+        proxy_pass http://{{.Name}};
+{{- end}}
+        proxy_http_version 1.1;
+        proxy_set_header Host $host;
+        proxy_set_header X-Real-IP $remote_addr;
+        proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+        proxy_set_header X-Forwarded-Proto $scheme;
+        proxy_set_header Upgrade $http_upgrade;
+        proxy_set_header Connection $connection_upgrade_{{.Ident}};
+        proxy_read_timeout 60s;
+    }
+}
+`
@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// DevcontainerDir holds the dev container configuration
+const DevcontainerDir = ".devcontainer"
+
+// devcontainer is the subset of devcontainer.json written for a project
+type devcontainer struct {
+	Name              string            `json:"name"`
+	DockerComposeFile []string          `json:"dockerComposeFile"`
+	Service           string            `json:"service"`
+	RunServices       []string          `json:"runServices"`
+	WorkspaceFolder   string            `json:"workspaceFolder"`
+	ForwardPorts      []json.Number     `json:"forwardPorts"`
+	RemoteEnv         map[string]string `json:"remoteEnv"`
+	PostCreateCommand string            `json:"postCreateCommand,omitempty"`
+	Customizations    customizations    `json:"customizations"`
+}
+
+type customizations struct {
+	VSCode vscode `json:"vscode"`
+}
+
+type vscode struct {
+	Extensions []string `json:"extensions"`
+}
+
+// DevcontainerFiles renders .devcontainer/devcontainer.json, a development
+// Dockerfile and a compose override. The override extends the project's
+// compose.yaml (see Files), so docker compose keeps reading .env from the
+// project root. postCreate commands run once the container is created.
+func DevcontainerFiles(opts Options, postCreate []string) ([]File, error) {
+	if opts.PHPVersion == "" {
+		opts.PHPVersion = DefaultPHPVersion
+	}
+	dockerfile, err := render("devcontainer "+Dockerfile, devDockerfileTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	override, err := render("devcontainer "+ComposeFile, devComposeTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	config := devcontainer{
+		Name:              opts.ProjectName + " (" + opts.Profile.Name + ")",
+		DockerComposeFile: []string{"../" + ComposeFile, ComposeFile},
+		Service:           ServiceApp,
+		RunServices:       []string{ServiceApp, ServiceDB, ServiceRedis},
+		WorkspaceFolder:   WorkDir,
+		ForwardPorts:      []json.Number{json.Number(opts.AppPort)},
+		RemoteEnv:         map[string]string{"COMPOSER_ALLOW_SUPERUSER": "1"},
+		PostCreateCommand: strings.Join(postCreate, " && "),
+		Customizations: customizations{VSCode: vscode{Extensions: []string{
+			"bmewburn.vscode-intelephense-client",
+			"xdebug.php-debug",
+		}}},
+	}
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(config); err != nil {
+		return nil, err
+	}
+
+	return []File{
+		{Path: path.Join(DevcontainerDir, "devcontainer.json"), Content: data.String()},
+		{Path: path.Join(DevcontainerDir, Dockerfile), Content: dockerfile},
+		{Path: path.Join(DevcontainerDir, ComposeFile), Content: override},
+	}, nil
+}
+
+const devDockerfileTemplate = `# Generated by mine: development image for the {{.Profile.Name}} platform
+FROM {{.BaseImage}}
+
+# --image may be any base image, so use the package manager it comes with
+RUN if command -v apk > /dev/null; then \
+        apk add --no-cache bash git openssh-client; \
+    elif command -v apt-get > /dev/null; then \
+        apt-get update && apt-get install -y --no-install-recommends bash git openssh-client \
+        && rm -rf /var/lib/apt/lists/*; \
+    else \
+        echo "install bash, git and openssh-client: no apk or apt-get in {{.BaseImage}}" >&2; exit 1; \
+    fi
+` + iniTemplate + `
+
+WORKDIR ` + WorkDir + `
+`
+
+// devComposeTemplate overrides the app service of the project's compose.yaml.
+// Paths are relative to the project root, where the first compose file is.
+const devComposeTemplate = `# Generated by mine. Extends ../compose.yaml for the dev container.
+services:
+  ` + ServiceApp + `:
+    build:
+      context: .
+      dockerfile: ` + DevcontainerDir + `/` + Dockerfile + `
+    command: ["sleep", "infinity"]
+`
@@ -0,0 +1,231 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/mineadmin/mine/internal/platform"
+)
+
+// Service names in the generated compose file
+const (
+	ServiceApp   = "app"
+	ServiceDB    = "db"
+	ServiceRedis = "redis"
+)
+
+// WorkDir is where the project lives inside the app container
+const WorkDir = "/opt/www"
+
+// DefaultPHPVersion is the PHP version of the base image when none is given
+const DefaultPHPVersion = "8.1"
+
+// Generated file names, relative to the project root
+const (
+	Dockerfile   = "Dockerfile"
+	ComposeFile  = "compose.yaml"
+	DockerIgnore = ".dockerignore"
+)
+
+// Options describe the project the files are generated for
+type Options struct {
+	ProjectName string
+	Profile     platform.Profile
+	PHPVersion  string
+	// Image overrides the platform's base image
+	Image    string
+	AppPort  string
+	DBDriver string
+	DBUser   string
+	// EmptyDBPassword and RedisPassword tell how the services must be secured;
+	// the values themselves are read from .env by docker compose
+	EmptyDBPassword bool
+	RedisPassword   bool
+}
+
+// BaseImage returns the image the Dockerfile starts from
+func (o Options) BaseImage() string {
+	if o.Image != "" {
+		return o.Image
+	}
+	return o.Profile.ImageFor(o.PHPVersion)
+}
+
+// DBPort returns the port the database listens on inside its container
+func DBPort(driver string) string {
+	if driver == "pgsql" {
+		return "5432"
+	}
+	return "3306"
+}
+
+// File is a generated file
+type File struct {
+	Path    string
+	Content string
+}
+
+// Files renders the Dockerfile, compose file and .dockerignore
+func Files(opts Options) ([]File, error) {
+	if opts.Profile.Image == "" && opts.Image == "" {
+		return nil, fmt.Errorf("platform %s has no Docker image, pass one with --image", opts.Profile.Name)
+	}
+	if opts.PHPVersion == "" {
+		opts.PHPVersion = DefaultPHPVersion
+	}
+
+	var files []File
+	for _, f := range []struct {
+		path string
+		text string
+	}{
+		{Dockerfile, dockerfileTemplate},
+		{ComposeFile, composeTemplate},
+		{DockerIgnore, dockerIgnoreTemplate},
+	} {
+		content, err := render(f.path, f.text, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: f.path, Content: content})
+	}
+	return files, nil
+}
+
+func render(name, text string, opts Options) (string, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"dbPort": DBPort,
+	}).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", name, err)
+	}
+	return buf.String(), nil
+}
+
+const dockerfileTemplate = `# Generated by mine for the {{.Profile.Name}} platform
+FROM {{.BaseImage}}
+
+WORKDIR ` + WorkDir + `
+` + iniTemplate + `
+
+COPY composer.json composer.lock* ./
+RUN composer install --no-dev --no-scripts --no-autoloader --prefer-dist
+
+COPY . .
+RUN composer dump-autoload --optimize --no-dev
+
+EXPOSE {{.AppPort}}
+
+CMD ["php", "bin/hyperf.php", "start"]
+`
+
+// iniTemplate writes the platform's ini settings into the image
+const iniTemplate = `{{- if .Profile.Ini}}
+
+RUN { \
+{{- range .Profile.Ini}}
+        echo "{{.Name}} = {{.Value}}"; \
+{{- end}}
+    } > "$(php -r 'echo PHP_CONFIG_FILE_SCAN_DIR;')/99-mine.ini"
+{{- end}}`
+
+const composeTemplate = `# Generated by mine. Credentials and ports come from .env, which docker
+# compose reads automatically; the app container reaches the services by name.
+name: {{.ProjectName}}
+
+services:
+  ` + ServiceApp + `:
+    build: .
+    env_file: .env
+    environment:
+      DB_HOST: ` + ServiceDB + `
+      DB_PORT: "{{dbPort .DBDriver}}"
+      REDIS_HOST: ` + ServiceRedis + `
+      REDIS_PORT: "6379"
+    ports:
+      - "{{.AppPort}}:{{.AppPort}}"
+    volumes:
+      - ./:` + WorkDir + `
+    depends_on:
+      ` + ServiceDB + `:
+        condition: service_healthy
+      ` + ServiceRedis + `:
+        condition: service_healthy
+
+  ` + ServiceDB + `:
+{{- if eq .DBDriver "pgsql"}}
+    image: postgres:16-alpine
+    environment:
+      POSTGRES_DB: ${DB_DATABASE}
+      POSTGRES_USER: ${DB_USERNAME}
+{{- if .EmptyDBPassword}}
+      POSTGRES_HOST_AUTH_METHOD: trust
+{{- else}}
+      POSTGRES_PASSWORD: ${DB_PASSWORD}
+{{- end}}
+    ports:
+      - "${DB_PORT}:5432"
+    volumes:
+      - db-data:/var/lib/postgresql/data
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U ${DB_USERNAME} -d ${DB_DATABASE}"]
+{{- else}}
+    image: mysql:8.0
+    environment:
+      MYSQL_DATABASE: ${DB_DATABASE}
+{{- if eq .DBUser "root"}}
+{{- if .EmptyDBPassword}}
+      MYSQL_ALLOW_EMPTY_PASSWORD: "yes"
+{{- else}}
+      MYSQL_ROOT_PASSWORD: ${DB_PASSWORD}
+{{- end}}
+{{- else}}
+      MYSQL_USER: ${DB_USERNAME}
+      MYSQL_PASSWORD: ${DB_PASSWORD}
+      MYSQL_RANDOM_ROOT_PASSWORD: "yes"
+{{- end}}
+    ports:
+      - "${DB_PORT}:3306"
+    volumes:
+      - db-data:/var/lib/mysql
+    healthcheck:
+      test: ["CMD", "mysqladmin", "ping", "-h", "127.0.0.1", "--silent"]
+{{- end}}
+      interval: 5s
+      timeout: 5s
+      retries: 30
+
+  ` + ServiceRedis + `:
+    image: redis:7-alpine
+{{- if .RedisPassword}}
+    command: ["redis-server", "--requirepass", "${REDIS_AUTH}"]
+{{- end}}
+    ports:
+      - "${REDIS_PORT}:6379"
+    healthcheck:
+{{- if .RedisPassword}}
+      test: ["CMD", "redis-cli", "-a", "${REDIS_AUTH}", "--no-auth-warning", "ping"]
+{{- else}}
+      test: ["CMD", "redis-cli", "ping"]
+{{- end}}
+      interval: 5s
+      timeout: 5s
+      retries: 30
+
+volumes:
+  db-data:
+`
+
+const dockerIgnoreTemplate = `.git
+.mine
+.env
+vendor
+runtime
+web/node_modules
+*.zip
+`
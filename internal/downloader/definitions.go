@@ -0,0 +1,52 @@
+package downloader
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// Definition describes a language's project: where its releases live and how they are laid out
+type Definition struct {
+	// Repo is the default repository, as owner/name; the configured source may override it
+	Repo string
+	// TagPattern selects the release tags that are project versions; empty allows all
+	TagPattern *regexp.Regexp
+	// Subdir is the directory inside the archive holding the project, below
+	// the archive's top-level directory; empty for the archive root
+	Subdir string
+	// PostInstall are run in the project directory after extraction
+	PostInstall []utils.Command
+}
+
+// definitions are the projects per language. PHP dependencies are
+// installed by the create steps, so it has no post-install commands.
+var definitions = map[string]Definition{
+	"php": {
+		Repo: "mineadmin/mineadmin",
+	},
+	"go": {
+		Repo:       "mineadmin/mineadmin-go",
+		TagPattern: regexp.MustCompile(`^v\d+\.\d+\.\d+$`),
+		PostInstall: []utils.Command{
+			{Name: "go", Args: []string{"mod", "download"}},
+		},
+	},
+	"js": {
+		Repo:       "mineadmin/mineadmin-js",
+		TagPattern: regexp.MustCompile(`^v\d+\.\d+\.\d+$`),
+		PostInstall: []utils.Command{
+			{Name: "npm", Args: []string{"install"}},
+		},
+	},
+}
+
+// DefinitionFor returns the project definition of a language
+func DefinitionFor(language string) (Definition, error) {
+	def, ok := definitions[language]
+	if !ok {
+		return Definition{}, fmt.Errorf("no project definition for language %q", language)
+	}
+	return def, nil
+}
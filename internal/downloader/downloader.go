@@ -2,96 +2,54 @@ package downloader
 
 import (
 	"archive/zip"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/mineadmin/mine/internal/prompt"
+	"github.com/mineadmin/mine/internal/source"
 )
 
-const (
-	baseURL = "https://github.com/mineadmin/mineadmin/archive/refs/tags"
-)
-
+// Downloader fetches a language's project from a source
 type Downloader struct {
 	Language string
-	Version  string
-	Platform string
+	// Version is the release tag, branch or commit to download
+	Version string
+	Source  source.Source
 }
 
-func NewDownloader(language, version, platform string) *Downloader {
+func NewDownloader(language, version string, src source.Source) *Downloader {
 	return &Downloader{
 		Language: language,
 		Version:  version,
-		Platform: platform,
+		Source:   src,
 	}
 }
 
-func (d *Downloader) Download(projectName string) error {
-	// For PHP projects, download the source zip from GitHub releases
-	if d.Language == "php" {
-		url := fmt.Sprintf("%s/%s.zip", baseURL, d.Version)
-
-		prompt.Info("Creating project directory...")
-		spinner := prompt.StartSpinner("Setting up project structure")
-		if err := os.MkdirAll(projectName, 0755); err != nil {
-			spinner.Stop()
-			return fmt.Errorf("failed to create project directory: %v", err)
-		}
-		spinner.Stop()
-
-		// Download the file
-		prompt.Info("Downloading project files...")
-		spinner = prompt.StartSpinner("Fetching MineAdmin source code")
-		resp, err := http.Get(url)
-		if err != nil {
-			spinner.Stop()
-			return fmt.Errorf("failed to download: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			spinner.Stop()
-			return fmt.Errorf("download failed with status: %s", resp.Status)
-		}
-
-		// Create the output file
-		outputPath := filepath.Join(projectName, fmt.Sprintf("%s.zip", d.Version))
-		out, err := os.Create(outputPath)
-		if err != nil {
-			spinner.Stop()
-			return fmt.Errorf("failed to create output file: %v", err)
-		}
-		defer out.Close()
-
-		// Write the body to file
-		_, err = io.Copy(out, resp.Body)
-		if err != nil {
-			spinner.Stop()
-			return fmt.Errorf("failed to write file: %v", err)
-		}
-		spinner.Stop()
-		prompt.Success("Download completed")
-
-		// Unzip the file
-		prompt.Info("Extracting project files...")
-		spinner = prompt.StartSpinner("Unpacking MineAdmin source code")
-		if err := unzip(outputPath, projectName); err != nil {
-			spinner.Stop()
-			return fmt.Errorf("failed to unzip: %v", err)
-		}
-		spinner.Stop()
-		prompt.Success("Extraction completed")
+// URL returns the archive URL the downloader fetches
+func (d *Downloader) URL() string {
+	return d.Source.ArchiveURL(d.Version)
+}
 
-		return nil
+// Download fetches and extracts the project archive into projectName.
+// A project directory created by this call is removed again if the download
+// fails or ctx is cancelled, so no partial project is left behind.
+func (d *Downloader) Download(ctx context.Context, projectName string) (err error) {
+	def, err := DefinitionFor(d.Language)
+	if err != nil {
+		return err
 	}
 
-	// Original download logic for other languages
-	url := fmt.Sprintf("%s/%s/mineadmin-%s-%s.zip", baseURL, d.Version, d.Language, d.Platform)
+	_, statErr := os.Stat(projectName)
+	createdDir := os.IsNotExist(statErr)
+	defer func() {
+		if err != nil && createdDir {
+			os.RemoveAll(projectName)
+		}
+	}()
 
 	prompt.Info("Creating project directory...")
 	spinner := prompt.StartSpinner("Setting up project structure")
@@ -101,113 +59,136 @@ func (d *Downloader) Download(projectName string) error {
 	}
 	spinner.Stop()
 
+	// Branch refs such as feature/x contain slashes
+	outputPath := filepath.Join(projectName, strings.ReplaceAll(d.Version, "/", "-")+".zip")
+
 	// Download the file
 	prompt.Info("Downloading project files...")
 	spinner = prompt.StartSpinner("Fetching MineAdmin source code")
-	resp, err := http.Get(url)
-	if err != nil {
+	if err := d.fetch(ctx, outputPath); err != nil {
 		spinner.Stop()
-		return fmt.Errorf("failed to download: %v", err)
+		return err
 	}
-	defer resp.Body.Close()
+	spinner.Stop()
+	prompt.Success("Download completed")
 
-	if resp.StatusCode != http.StatusOK {
+	// Unzip the file
+	prompt.Info("Extracting project files...")
+	spinner = prompt.StartSpinner("Unpacking MineAdmin source code")
+	if err := unzip(ctx, outputPath, projectName, def.Subdir); err != nil {
 		spinner.Stop()
-		return fmt.Errorf("download failed with status: %s", resp.Status)
+		return fmt.Errorf("failed to unzip: %v", err)
 	}
+	spinner.Stop()
+	prompt.Success("Extraction completed")
+
+	return nil
+}
+
+// fetch downloads the archive into outputPath, removing the file again on failure
+func (d *Downloader) fetch(ctx context.Context, outputPath string) (err error) {
+	archive, err := d.Source.Archive(ctx, d.Version)
+	if err != nil {
+		return fmt.Errorf("failed to download: %v", err)
+	}
+	defer archive.Close()
 
 	// Create the output file
-	outputPath := filepath.Join(projectName, fmt.Sprintf("mineadmin-%s-%s.zip", d.Language, d.Platform))
 	out, err := os.Create(outputPath)
 	if err != nil {
-		spinner.Stop()
 		return fmt.Errorf("failed to create output file: %v", err)
 	}
-	defer out.Close()
+	defer func() {
+		out.Close()
+		if err != nil {
+			os.Remove(outputPath)
+		}
+	}()
 
 	// Write the body to file
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		spinner.Stop()
+	if _, err := io.Copy(out, archive); err != nil {
 		return fmt.Errorf("failed to write file: %v", err)
 	}
-	spinner.Stop()
-	prompt.Success("Download completed")
-
 	return nil
 }
 
-func (d *Downloader) ListVersions() ([]string, error) {
-	if d.Language == "php" {
-		resp, err := http.Get("https://api.github.com/repos/mineadmin/mineadmin/releases")
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		var releases []struct {
-			TagName string `json:"tag_name"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-			return nil, err
-		}
+// ListVersions returns the release tags of the source that are project versions
+func (d *Downloader) ListVersions(ctx context.Context) ([]string, error) {
+	def, err := DefinitionFor(d.Language)
+	if err != nil {
+		return nil, err
+	}
 
-		var versions []string
-		for _, r := range releases {
-			versions = append(versions, r.TagName)
+	tags, err := d.Source.ListReleases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases of %s: %v", d.Source.Name(), err)
+	}
+	var versions []string
+	for _, tag := range tags {
+		if def.TagPattern == nil || def.TagPattern.MatchString(tag) {
+			versions = append(versions, tag)
 		}
-		return versions, nil
 	}
-
-	// For other languages, return mock data
-	return []string{"v1.0.0", "v1.0.1", "v1.1.0"}, nil
+	return versions, nil
 }
 
-func unzip(src, dest string) error {
+// unzip extracts the files below subdir of the archive's top-level directory into dest
+func unzip(ctx context.Context, src, dest, subdir string) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
+	prefix := ""
+	if subdir != "" {
+		prefix = strings.Trim(subdir, "/") + "/"
+	}
 	for _, f := range r.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// Skip directories
 		if f.FileInfo().IsDir() {
 			continue
 		}
 
-		// Remove the top-level directory from the path
-		parts := strings.Split(f.Name, string(filepath.Separator))
-		if len(parts) < 2 {
-			continue // Skip files in root directory
+		// Remove the top-level directory from the path; zip paths always use slashes
+		parts := strings.SplitN(f.Name, "/", 2)
+		if len(parts) < 2 || !strings.HasPrefix(parts[1], prefix) {
+			continue // Skip files in root directory or outside subdir
 		}
-		relPath := filepath.Join(parts[1:]...)
-
-		// Open the file inside the zip
-		rc, err := f.Open()
-		if err != nil {
-			return err
+		relPath := strings.TrimPrefix(parts[1], prefix)
+		path := filepath.Join(dest, filepath.FromSlash(relPath))
+		if !strings.HasPrefix(path, filepath.Clean(dest)+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s escapes the project directory", f.Name)
 		}
-		defer rc.Close()
-
-		// Create the output file
-		path := filepath.Join(dest, relPath)
-		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		if err := extractFile(f, path); err != nil {
 			return err
 		}
+	}
 
-		out, err := os.Create(path)
-		if err != nil {
-			return err
-		}
-		defer out.Close()
+	return nil
+}
 
-		// Copy the contents
-		_, err = io.Copy(out, rc)
-		if err != nil {
-			return err
-		}
+// extractFile writes a single archive entry to path
+func extractFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
 	}
+	defer rc.Close()
 
-	return nil
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm()|0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
 }
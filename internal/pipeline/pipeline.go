@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/prompt"
+)
+
+// Step is a named unit of a pipeline
+type Step struct {
+	Name        string
+	Description string
+	Run         func(ctx context.Context) error
+	// Plan describes what Run would do without side effects. Optional.
+	Plan func(ctx context.Context) error
+}
+
+// Options controls which steps of a pipeline run
+type Options struct {
+	// Resume skips steps already recorded as completed
+	Resume bool
+	// Skip lists steps that must not run
+	Skip []string
+	// Only, when set, lists the only steps that may run
+	Only []string
+	// DryRun plans the selected steps instead of running them and records nothing
+	DryRun bool
+	// Known lists step names Skip and Only accept besides the pipeline's own,
+	// such as steps of optional features that are not enabled. They do nothing.
+	Known []string
+}
+
+// StepError reports the step a pipeline stopped at
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %s failed: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline runs steps in order and records progress in the project directory
+type Pipeline struct {
+	steps      []Step
+	state      *State
+	projectDir string
+}
+
+// New creates a pipeline whose state is saved in projectDir
+func New(projectDir string, state *State, steps ...Step) *Pipeline {
+	return &Pipeline{steps: steps, state: state, projectDir: projectDir}
+}
+
+// Names returns the step names in order
+func (p *Pipeline) Names() []string {
+	names := make([]string, len(p.steps))
+	for i, s := range p.steps {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Validate checks that every step named in opts exists or is known
+func (p *Pipeline) Validate(opts Options) error {
+	valid := p.Names()
+	for _, name := range opts.Known {
+		if !contains(valid, name) {
+			valid = append(valid, name)
+		}
+	}
+	for _, name := range append(append([]string{}, opts.Skip...), opts.Only...) {
+		if !contains(valid, name) {
+			return fmt.Errorf("unknown step %q, expected one of %s", name, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// Run executes the selected steps, saving state after each completed one.
+// It stops before the next step once ctx is cancelled.
+func (p *Pipeline) Run(ctx context.Context, opts Options) error {
+	if err := p.Validate(opts); err != nil {
+		return err
+	}
+
+	for _, step := range p.steps {
+		if err := ctx.Err(); err != nil {
+			return &StepError{Step: step.Name, Err: err}
+		}
+
+		switch {
+		case contains(opts.Skip, step.Name):
+			prompt.Debug(fmt.Sprintf("Skipping step %s", step.Name))
+			continue
+		case len(opts.Only) > 0 && !contains(opts.Only, step.Name):
+			prompt.Debug(fmt.Sprintf("Skipping step %s (not selected)", step.Name))
+			continue
+		case opts.Resume && len(opts.Only) == 0 && p.state.IsCompleted(step.Name):
+			prompt.Info(fmt.Sprintf("Step %s already completed", step.Name))
+			continue
+		}
+
+		if opts.DryRun {
+			prompt.Info(fmt.Sprintf("[dry-run] %s: %s", step.Name, step.Description))
+			if step.Plan != nil {
+				if err := step.Plan(ctx); err != nil {
+					return &StepError{Step: step.Name, Err: err}
+				}
+			}
+			continue
+		}
+
+		prompt.Debug(fmt.Sprintf("Running step %s: %s", step.Name, step.Description))
+		if err := step.Run(ctx); err != nil {
+			return &StepError{Step: step.Name, Err: err}
+		}
+
+		p.state.MarkCompleted(step.Name)
+		if err := p.state.Save(p.projectDir); err != nil {
+			return fmt.Errorf("failed to record step %s: %v", step.Name, err)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
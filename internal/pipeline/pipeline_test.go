@@ -0,0 +1,169 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recorder builds steps that record their runs and plans
+type recorder struct {
+	ran, planned []string
+	fail         string
+}
+
+func (r *recorder) steps(names ...string) []Step {
+	steps := make([]Step, len(names))
+	for i, name := range names {
+		name := name
+		steps[i] = Step{
+			Name: name,
+			Run: func(ctx context.Context) error {
+				r.ran = append(r.ran, name)
+				if name == r.fail {
+					return errors.New("boom")
+				}
+				return nil
+			},
+			Plan: func(ctx context.Context) error {
+				r.planned = append(r.planned, name)
+				return nil
+			},
+		}
+	}
+	return steps
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		completed []string
+		opts      Options
+		ran       []string
+		planned   []string
+		completes []string
+	}{
+		{
+			name:      "all steps",
+			ran:       []string{"download", "configure", "install"},
+			completes: []string{"download", "configure", "install"},
+		},
+		{
+			name:      "resume skips completed steps",
+			completed: []string{"download"},
+			opts:      Options{Resume: true},
+			ran:       []string{"configure", "install"},
+			completes: []string{"download", "configure", "install"},
+		},
+		{
+			name:      "without resume completed steps run again",
+			completed: []string{"download"},
+			ran:       []string{"download", "configure", "install"},
+			completes: []string{"download", "configure", "install"},
+		},
+		{
+			name:      "skip",
+			opts:      Options{Skip: []string{"configure"}},
+			ran:       []string{"download", "install"},
+			completes: []string{"download", "install"},
+		},
+		{
+			name:      "only runs completed steps again when resuming",
+			completed: []string{"download", "configure"},
+			opts:      Options{Resume: true, Only: []string{"configure"}},
+			ran:       []string{"configure"},
+			completes: []string{"download", "configure"},
+		},
+		{
+			name:    "dry run plans and records nothing",
+			opts:    Options{DryRun: true, Skip: []string{"install"}},
+			planned: []string{"download", "configure"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			r := &recorder{}
+			state := &State{Completed: append([]string{}, tt.completed...)}
+			p := New(dir, state, r.steps("download", "configure", "install")...)
+			if err := p.Run(context.Background(), tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.ran, tt.ran) {
+				t.Errorf("ran %v, want %v", r.ran, tt.ran)
+			}
+			if !reflect.DeepEqual(r.planned, tt.planned) {
+				t.Errorf("planned %v, want %v", r.planned, tt.planned)
+			}
+
+			saved, err := LoadState(dir)
+			if tt.completes == nil {
+				if err == nil {
+					t.Errorf("state saved with %v", saved.Completed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(saved.Completed, tt.completes) {
+				t.Errorf("saved %v, want %v", saved.Completed, tt.completes)
+			}
+		})
+	}
+}
+
+func TestRunStopsAtFailedStep(t *testing.T) {
+	dir := t.TempDir()
+	r := &recorder{fail: "configure"}
+	p := New(dir, &State{}, r.steps("download", "configure", "install")...)
+
+	err := p.Run(context.Background(), Options{})
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "configure" {
+		t.Fatalf("error = %v, want a StepError for configure", err)
+	}
+	if !reflect.DeepEqual(r.ran, []string{"download", "configure"}) {
+		t.Errorf("ran %v", r.ran)
+	}
+
+	// Resuming continues at the failed step
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ran, r.fail = nil, ""
+	if err := New(dir, state, r.steps("download", "configure", "install")...).Run(context.Background(), Options{Resume: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.ran, []string{"configure", "install"}) {
+		t.Errorf("resumed run ran %v", r.ran)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := &recorder{}
+	err := New(t.TempDir(), &State{}, r.steps("download")...).Run(ctx, Options{})
+	if !errors.Is(err, context.Canceled) || len(r.ran) != 0 {
+		t.Errorf("error = %v, ran %v", err, r.ran)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := New(t.TempDir(), &State{}, (&recorder{}).steps("download", "configure")...)
+	if err := p.Validate(Options{Skip: []string{"configure"}, Only: []string{"download"}}); err != nil {
+		t.Error(err)
+	}
+	if err := p.Validate(Options{Skip: []string{"configur"}}); err == nil {
+		t.Error("unknown step accepted")
+	}
+	if err := p.Run(context.Background(), Options{Only: []string{"migrate"}}); err == nil {
+		t.Error("Run accepted an unknown step")
+	}
+	if err := p.Validate(Options{Skip: []string{"frontend"}, Known: []string{"download", "frontend"}}); err != nil {
+		t.Errorf("known step outside the pipeline rejected: %v", err)
+	}
+}
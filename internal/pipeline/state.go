@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// StateDir is the directory inside a project where the CLI keeps its metadata
+	StateDir = ".mine"
+	// StateFile is the name of the state file inside StateDir
+	StateFile = "state.json"
+)
+
+// State records how a project was created and which steps have completed
+type State struct {
+	Language     string            `json:"language"`
+	ProjectRoot  string            `json:"project_root,omitempty"`
+	Version      string            `json:"version"`
+	Platform     string            `json:"platform"`
+	Ref          string            `json:"ref,omitempty"`
+	Repo         string            `json:"repo,omitempty"`
+	Frontend     bool              `json:"frontend,omitempty"`
+	Seed         *bool             `json:"seed,omitempty"`
+	Docker       bool              `json:"docker,omitempty"`
+	DockerSetup  bool              `json:"docker_setup,omitempty"`
+	Template     string            `json:"template,omitempty"`
+	TemplateVars map[string]string `json:"template_vars,omitempty"`
+	Completed    []string          `json:"completed"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// StatePath returns the state file location for a project directory
+func StatePath(projectDir string) string {
+	return filepath.Join(projectDir, StateDir, StateFile)
+}
+
+// LoadState reads the state file of a project directory
+func LoadState(projectDir string) (*State, error) {
+	data, err := ioutil.ReadFile(StatePath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s is not a project created by mine (no %s found)", projectDir, filepath.Join(StateDir, StateFile))
+		}
+		return nil, fmt.Errorf("failed to read state: %v", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %v", err)
+	}
+	return &state, nil
+}
+
+// Save writes the state file into a project directory
+func (s *State) Save(projectDir string) error {
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	path := StatePath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %v", err)
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// IsCompleted reports whether a step has completed
+func (s *State) IsCompleted(step string) bool {
+	for _, c := range s.Completed {
+		if c == step {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkCompleted records a step as completed
+func (s *State) MarkCompleted(step string) {
+	if !s.IsCompleted(step) {
+		s.Completed = append(s.Completed, step)
+	}
+}
@@ -0,0 +1,155 @@
+package platform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/composer"
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// Profile describes what a project needs to run on a platform
+type Profile struct {
+	Name        string
+	Description string
+	// Upstream profiles are what MineAdmin releases already target, so a
+	// freshly downloaded project needs no overlay
+	Upstream   bool
+	Extensions []Extension
+	Ini        []IniSetting
+	// Image is the Docker base image, with %s standing for the PHP version
+	Image string
+	// Overlays are ordered newest version range first
+	Overlays []Overlay
+}
+
+// Extension is a PHP extension a platform requires
+type Extension struct {
+	Name string
+	URL  string
+}
+
+// IniSetting is a php.ini value a platform requires
+type IniSetting struct {
+	Name  string
+	Value string
+}
+
+// Matches reports whether value, as returned by ini_get, satisfies the
+// setting. Boolean settings accept any of PHP's spellings.
+func (s IniSetting) Matches(value string) bool {
+	if want, ok := iniBool(s.Value); ok {
+		got, ok := iniBool(value)
+		return ok && got == want
+	}
+	return value == s.Value
+}
+
+func iniBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "1", "on", "true", "yes":
+		return true, true
+	case "", "0", "off", "false", "no", "none":
+		return false, true
+	}
+	return false, false
+}
+
+// Overlay lists the changes that put a project of a version range on the platform
+type Overlay struct {
+	// Versions is a space separated list of constraints such as ">3.0 <4.0", or "*"
+	Versions string
+	Files    []File
+	Composer []composer.PackageChange
+}
+
+// File is fetched from Source in the MineAdmin repository to Target in the project
+type File struct {
+	Source string
+	Target string
+}
+
+// OverlayFor returns the first overlay whose range contains version, or nil.
+// An empty or "latest" version is a development ref and gets the newest overlay.
+func (p Profile) OverlayFor(version string) *Overlay {
+	if version == "" || version == "latest" {
+		if len(p.Overlays) == 0 {
+			return nil
+		}
+		return &p.Overlays[0]
+	}
+	for i := range p.Overlays {
+		if VersionInRange(version, p.Overlays[i].Versions) {
+			return &p.Overlays[i]
+		}
+	}
+	return nil
+}
+
+// ImageFor returns the Docker base image for a PHP version such as "8.1"
+func (p Profile) ImageFor(phpVersion string) string {
+	return fmt.Sprintf(p.Image, phpVersion)
+}
+
+// Lookup returns the profile called name
+func Lookup(name string) (Profile, bool) {
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Get returns the profile called name or an error listing the known ones
+func Get(name string) (Profile, error) {
+	if p, ok := Lookup(name); ok {
+		return p, nil
+	}
+	return Profile{}, utils.UnknownChoice("platform", name, Names())
+}
+
+// All returns every profile
+func All() []Profile {
+	return profiles
+}
+
+// Names returns the profile names, sorted
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VersionInRange reports whether version satisfies every constraint in
+// versions. Constraints are an operator (>, >=, <, <=, =) and a version.
+func VersionInRange(version, versions string) bool {
+	for _, constraint := range strings.Fields(versions) {
+		if constraint == "*" {
+			continue
+		}
+		op := strings.TrimRight(constraint, "0123456789.v")
+		cmp := utils.CompareVersions(version, strings.TrimPrefix(constraint, op))
+		var ok bool
+		switch op {
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package platform
+
+import "github.com/mineadmin/mine/internal/composer"
+
+// Project files that differ between the platforms
+const (
+	hyperfBin     = "bin/hyperf.php"
+	serverConfig  = "config/autoload/server.php"
+	testBootstrap = "tests/bootstrap.php"
+)
+
+// profiles are the known platforms; add a runtime by adding an entry here
+var profiles = []Profile{
+	{
+		Name:        "swow",
+		Description: "Swow coroutine engine (hyperf/engine-swow)",
+		Extensions:  []Extension{{Name: "swow", URL: "https://github.com/swow/swow"}},
+		Image:       "hyperf/hyperf:%s-alpine-v3.19-swow",
+		Overlays: []Overlay{
+			{
+				Versions: ">3.0",
+				Files: []File{
+					{Source: ".github/ci/hyperf.php", Target: hyperfBin},
+					{Source: ".github/ci/server.php", Target: serverConfig},
+					{Source: ".github/ci/bootstrap.php", Target: testBootstrap},
+				},
+				Composer: []composer.PackageChange{
+					{Section: composer.SectionRequire, Package: "ext-swoole"},
+					{Section: composer.SectionRequire, Package: "hyperf/engine-swow", Version: "*"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "swoole",
+		Description: "Swoole extension, the engine MineAdmin releases target",
+		Upstream:    true,
+		Extensions:  []Extension{{Name: "swoole", URL: "https://github.com/swoole/swoole-src"}},
+		Ini:         []IniSetting{{Name: "swoole.use_shortname", Value: "Off"}},
+		Image:       "hyperf/hyperf:%s-alpine-v3.19-swoole",
+		Overlays: []Overlay{
+			{
+				// Restores the upstream files when switching back from another platform
+				Versions: ">3.0",
+				Files: []File{
+					{Source: hyperfBin, Target: hyperfBin},
+					{Source: serverConfig, Target: serverConfig},
+					{Source: testBootstrap, Target: testBootstrap},
+				},
+				Composer: []composer.PackageChange{
+					{Section: composer.SectionRequire, Package: "hyperf/engine-swow"},
+					{Section: composer.SectionRequire, Package: "ext-swoole", Version: "*"},
+				},
+			},
+		},
+	},
+}
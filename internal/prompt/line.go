@@ -0,0 +1,132 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LinePrompter is a plain line based frontend used when stdin is not a TTY.
+// Each answer is read as one line, so answers can be piped into the CLI.
+type LinePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewLinePrompter creates a prompter reading answers from in and writing questions to out
+func NewLinePrompter(in io.Reader, out io.Writer) *LinePrompter {
+	return &LinePrompter{in: bufio.NewReader(in), out: out}
+}
+
+// readLine prints the question and reads one answer line
+func (p *LinePrompter) readLine(question string) (string, error) {
+	fmt.Fprint(p.out, question)
+	line, err := p.in.ReadString('\n')
+	// Piped answers are not echoed, so end the question line ourselves
+	fmt.Fprintln(p.out)
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("prompt failed: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ask reads answers until validate accepts one
+func (p *LinePrompter) ask(question string, validate func(string) error) (string, error) {
+	for {
+		answer, err := p.readLine(question)
+		if err != nil {
+			return "", err
+		}
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "✗ %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// Input reads a line, falling back to defaultValue on empty input
+func (p *LinePrompter) Input(label, defaultValue string, validate func(string) error) (string, error) {
+	question := fmt.Sprintf("%s: ", label)
+	if defaultValue != "" {
+		question = fmt.Sprintf("%s [%s]: ", label, defaultValue)
+	}
+
+	answer, err := p.ask(question, validate)
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// Password reads a secret line; there is no terminal to mask input on
+func (p *LinePrompter) Password(label string) (string, error) {
+	return p.readLine(fmt.Sprintf("%s: ", label))
+}
+
+// Select lists numbered options and accepts a number or the option text
+func (p *LinePrompter) Select(label string, options []string) (int, string, error) {
+	fmt.Fprintf(p.out, "%s?\n", label)
+	for i, o := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+
+	answer, err := p.ask("Choice: ", func(input string) error {
+		_, err := matchOption(input, options)
+		return err
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	index, _ := matchOption(answer, options)
+	return index, options[index], nil
+}
+
+// MultiSelect accepts a comma separated list of numbers or option texts
+func (p *LinePrompter) MultiSelect(label string, options []string, defaults []string) ([]string, error) {
+	fmt.Fprintf(p.out, "%s? (comma separated, empty keeps [%s])\n", label, strings.Join(defaults, ", "))
+	for i, o := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+
+	answer, err := p.ask("Choices: ", func(input string) error {
+		_, err := matchOptions(input, options)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(answer) == "" {
+		return defaults, nil
+	}
+	return matchOptions(answer, options)
+}
+
+// Confirm asks a yes/no question
+func (p *LinePrompter) Confirm(label string, defaultValue bool) (bool, error) {
+	answer, err := p.ask(fmt.Sprintf("%s %s: ", label, confirmHint(defaultValue)), func(input string) error {
+		_, err := parseConfirm(input, defaultValue)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return parseConfirm(answer, defaultValue)
+}
+
+// Number reads an integer within [min, max]
+func (p *LinePrompter) Number(label string, defaultValue, min, max int) (int, error) {
+	answer, err := p.ask(fmt.Sprintf("%s [%d]: ", label, defaultValue), numberValidator(min, max))
+	if err != nil {
+		return 0, err
+	}
+	return parseNumber(answer, defaultValue)
+}
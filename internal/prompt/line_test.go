@@ -0,0 +1,78 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinePrompter(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		"",       // Input: default
+		"bad",    // Input: rejected by the validator
+		"mine",   // Input: accepted
+		"s3cr3t", // Password
+		"oracle", // Select: not an option
+		"pgsql",  // Select
+		"2, 1",   // MultiSelect
+		"",       // MultiSelect: defaults
+		"yes",    // Confirm
+		"20",     // Number: out of range
+		"7",      // Number
+	}, "\n") + "\n")
+	var out bytes.Buffer
+	p := NewLinePrompter(in, &out)
+
+	if got, err := p.Input("Host", "127.0.0.1", nil); err != nil || got != "127.0.0.1" {
+		t.Errorf("Input = %q, %v, want the default", got, err)
+	}
+	validate := func(s string) error {
+		if s == "bad" {
+			return errors.New("not allowed")
+		}
+		return nil
+	}
+	if got, err := p.Input("Name", "", validate); err != nil || got != "mine" {
+		t.Errorf("Input = %q, %v", got, err)
+	}
+	if got, err := p.Password("Password"); err != nil || got != "s3cr3t" {
+		t.Errorf("Password = %q, %v", got, err)
+	}
+	if i, got, err := p.Select("Database type", []string{"mysql", "pgsql"}); err != nil || i != 1 || got != "pgsql" {
+		t.Errorf("Select = %d, %q, %v", i, got, err)
+	}
+	if got, err := p.MultiSelect("Services", []string{"mysql", "redis"}, nil); err != nil || !reflect.DeepEqual(got, []string{"redis", "mysql"}) {
+		t.Errorf("MultiSelect = %q, %v", got, err)
+	}
+	if got, err := p.MultiSelect("Services", []string{"mysql", "redis"}, []string{"redis"}); err != nil || !reflect.DeepEqual(got, []string{"redis"}) {
+		t.Errorf("MultiSelect = %q, %v, want the defaults", got, err)
+	}
+	if got, err := p.Confirm("Continue", false); err != nil || !got {
+		t.Errorf("Confirm = %v, %v", got, err)
+	}
+	if got, err := p.Number("Redis database", 0, 0, 15); err != nil || got != 7 {
+		t.Errorf("Number = %d, %v", got, err)
+	}
+
+	for _, want := range []string{"Host [127.0.0.1]: ", "✗ not allowed", "  2) pgsql", `"oracle" is not one of mysql, pgsql`, "between 0 and 15"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestLinePrompterLastLineWithoutNewline(t *testing.T) {
+	p := NewLinePrompter(strings.NewReader("mine"), &bytes.Buffer{})
+	if got, err := p.Input("Name", "", nil); err != nil || got != "mine" {
+		t.Errorf("Input = %q, %v", got, err)
+	}
+}
+
+func TestLinePrompterEOF(t *testing.T) {
+	p := NewLinePrompter(strings.NewReader(""), &bytes.Buffer{})
+	if _, err := p.Input("Name", "default", nil); err == nil {
+		t.Error("Input at end of input should fail instead of using the default")
+	}
+}
@@ -0,0 +1,175 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Level controls which messages are printed to the terminal
+type Level int
+
+const (
+	LevelError Level = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+)
+
+// String returns the tag used for the level in log files
+func (l Level) String() string {
+	switch l {
+	case LevelError:
+		return "ERROR"
+	case LevelWarn:
+		return "WARN"
+	case LevelDebug:
+		return "DEBUG"
+	default:
+		return "INFO"
+	}
+}
+
+var (
+	outputMu sync.Mutex
+	level    = LevelInfo
+	logFile  *os.File
+)
+
+// OutputOptions configures how messages are rendered
+type OutputOptions struct {
+	Quiet   bool
+	Verbose bool
+	NoColor bool
+	LogFile string
+}
+
+// ConfigureOutput applies the global output flags. Color is disabled when
+// requested, when NO_COLOR is set or when stdout is not a terminal.
+func ConfigureOutput(opts OutputOptions) error {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	switch {
+	case opts.Quiet:
+		level = LevelError
+	case opts.Verbose:
+		level = LevelDebug
+	default:
+		level = LevelInfo
+	}
+
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+	color.NoColor = opts.NoColor || noColorEnv || !IsTerminal(os.Stdout)
+
+	if opts.LogFile != "" {
+		f, err := os.OpenFile(opts.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %v", err)
+		}
+		logFile = f
+	}
+	return nil
+}
+
+// CloseOutput flushes and closes the log file if one is open
+func CloseOutput() error {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	return err
+}
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Enabled reports whether messages of level l are printed to the terminal
+func Enabled(l Level) bool {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	return l <= level
+}
+
+// Debug prints a diagnostic message, shown only in verbose mode
+func Debug(message string) {
+	prefix := color.New(color.FgWhite, color.Faint).Sprint(" DEBUG ")
+	content := color.New(color.Faint).Sprint(Redact(message))
+	emit(LevelDebug, message, fmt.Sprintf("%s %s\n", prefix, content))
+}
+
+// emit logs message and prints rendered when level is enabled. Errors go to stderr.
+func emit(l Level, message, rendered string) {
+	writeLog(l.String(), message)
+	if !Enabled(l) {
+		return
+	}
+
+	defer Suspend()()
+	if l == LevelError {
+		fmt.Fprint(color.Error, rendered)
+		return
+	}
+	fmt.Fprint(color.Output, rendered)
+}
+
+// writeLog appends a timestamped, redacted plain-text line to the log file
+func writeLog(tag, message string) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	if logFile == nil {
+		return
+	}
+	timestamp := time.Now().Format(time.RFC3339)
+	for _, line := range strings.Split(strings.TrimRight(Redact(message), "\n"), "\n") {
+		fmt.Fprintf(logFile, "%s [%s] %s\n", timestamp, tag, line)
+	}
+}
+
+// logWriter records everything written to it in the log file, line by line
+type logWriter struct {
+	tag string
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		writeLog(w.tag, string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// CommandOutput returns the writers subprocess stdout and stderr should be
+// attached to. Output is copied into the log file; stdout is hidden in quiet mode.
+func CommandOutput() (stdout io.Writer, stderr io.Writer) {
+	stdout = io.MultiWriter(os.Stdout, &logWriter{tag: "STDOUT"})
+	if !Enabled(LevelInfo) {
+		stdout = &logWriter{tag: "STDOUT"}
+	}
+	stderr = io.MultiWriter(os.Stderr, &logWriter{tag: "STDERR"})
+	return stdout, stderr
+}
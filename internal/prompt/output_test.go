@@ -0,0 +1,46 @@
+package prompt
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestPlain(t *testing.T) {
+	defer func(saved []string) { secrets = saved }(secrets)
+	secrets = nil
+	RegisterSecret("s3cr3t-pass")
+
+	defer func(saved io.Writer) { color.Output = saved }(color.Output)
+	var out bytes.Buffer
+	color.Output = &out
+
+	logPath := filepath.Join(t.TempDir(), "mine.log")
+	if err := ConfigureOutput(OutputOptions{Quiet: true, LogFile: logPath}); err != nil {
+		t.Fatal(err)
+	}
+	Plain("    DB_PASSWORD=s3cr3t-pass")
+	CloseOutput()
+
+	if out.Len() != 0 {
+		t.Errorf("quiet mode printed %q", out.String())
+	}
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "[INFO]     DB_PASSWORD=******") {
+		t.Errorf("log does not have the redacted line:\n%s", log)
+	}
+
+	ConfigureOutput(OutputOptions{})
+	Plain("    DB_PASSWORD=s3cr3t-pass")
+	if out.String() != "    DB_PASSWORD=******\n" {
+		t.Errorf("printed %q", out.String())
+	}
+}
@@ -0,0 +1,56 @@
+package prompt
+
+import (
+	"os"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Prompter is implemented by every frontend that can answer interactive questions
+type Prompter interface {
+	Input(label, defaultValue string, validate func(string) error) (string, error)
+	Password(label string) (string, error)
+	Select(label string, options []string) (int, string, error)
+	MultiSelect(label string, options []string, defaults []string) ([]string, error)
+	Confirm(label string, defaultValue bool) (bool, error)
+	Number(label string, defaultValue, min, max int) (int, error)
+}
+
+var (
+	prompterMu sync.RWMutex
+	prompter   Prompter
+)
+
+// NewDefaultPrompter returns the promptui frontend when stdin is a terminal,
+// and the plain line based frontend otherwise
+func NewDefaultPrompter() Prompter {
+	if isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return NewPromptuiPrompter()
+	}
+	return NewLinePrompter(os.Stdin, os.Stdout)
+}
+
+// SetPrompter replaces the frontend used by the package level prompt functions
+func SetPrompter(p Prompter) {
+	prompterMu.Lock()
+	defer prompterMu.Unlock()
+	prompter = p
+}
+
+// Current returns the frontend used by the package level prompt functions
+func Current() Prompter {
+	prompterMu.RLock()
+	p := prompter
+	prompterMu.RUnlock()
+	if p != nil {
+		return p
+	}
+
+	prompterMu.Lock()
+	defer prompterMu.Unlock()
+	if prompter == nil {
+		prompter = NewDefaultPrompter()
+	}
+	return prompter
+}
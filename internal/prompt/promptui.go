@@ -0,0 +1,233 @@
+package prompt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// PromptuiPrompter is the interactive terminal frontend backed by promptui
+type PromptuiPrompter struct{}
+
+// NewPromptuiPrompter creates a promptui based prompter
+func NewPromptuiPrompter() *PromptuiPrompter {
+	return &PromptuiPrompter{}
+}
+
+// Input prompts for user input, falling back to defaultValue on empty input
+func (p *PromptuiPrompter) Input(label, defaultValue string, validate func(string) error) (string, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   defaultValue,
+		Templates: getInputTemplates(),
+		Validate:  validate,
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %v", err)
+	}
+
+	if result == "" {
+		return defaultValue, nil
+	}
+	return result, nil
+}
+
+// Password prompts for a secret value without echoing it on screen
+func (p *PromptuiPrompter) Password(label string) (string, error) {
+	prompt := promptui.Prompt{
+		Label:       label,
+		Mask:        '*',
+		HideEntered: true,
+		Templates:   getInputTemplates(),
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %v", err)
+	}
+	return result, nil
+}
+
+// Select prompts user to select from a list of options
+func (p *PromptuiPrompter) Select(label string, options []string) (int, string, error) {
+	prompt := promptui.Select{
+		Label:     label,
+		Items:     options,
+		Templates: getSelectTemplates(),
+		Size:      10, // Show 10 items at a time
+	}
+
+	index, result, err := prompt.Run()
+	if err != nil {
+		return 0, "", fmt.Errorf("prompt failed: %v", err)
+	}
+
+	return index, result, nil
+}
+
+// MultiSelect lets the user toggle any number of options, finishing with "Done"
+func (p *PromptuiPrompter) MultiSelect(label string, options []string, defaults []string) ([]string, error) {
+	checked := make(map[string]bool, len(options))
+	for _, d := range defaults {
+		checked[d] = true
+	}
+
+	cursor := 0
+	for {
+		items := make([]string, 0, len(options)+1)
+		for _, o := range options {
+			mark := "[ ]"
+			if checked[o] {
+				mark = "[x]"
+			}
+			items = append(items, fmt.Sprintf("%s %s", mark, o))
+		}
+		items = append(items, multiSelectDone)
+
+		prompt := promptui.Select{
+			Label:        label,
+			Items:        items,
+			Templates:    getSelectTemplates(),
+			Size:         10,
+			CursorPos:    cursor,
+			HideSelected: true,
+		}
+
+		index, _, err := prompt.Run()
+		if err != nil {
+			return nil, fmt.Errorf("prompt failed: %v", err)
+		}
+		if index == len(options) {
+			break
+		}
+
+		checked[options[index]] = !checked[options[index]]
+		cursor = index
+	}
+
+	var selected []string
+	for _, o := range options {
+		if checked[o] {
+			selected = append(selected, o)
+		}
+	}
+	return selected, nil
+}
+
+// Confirm asks a yes/no question
+func (p *PromptuiPrompter) Confirm(label string, defaultValue bool) (bool, error) {
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("%s %s", label, confirmHint(defaultValue)),
+		Templates: getInputTemplates(),
+		Validate: func(input string) error {
+			_, err := parseConfirm(input, defaultValue)
+			return err
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return false, fmt.Errorf("prompt failed: %v", err)
+	}
+	return parseConfirm(result, defaultValue)
+}
+
+// Number prompts for numeric input with range validation
+func (p *PromptuiPrompter) Number(label string, defaultValue, min, max int) (int, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   strconv.Itoa(defaultValue),
+		Templates: getInputTemplates(),
+		Validate:  numberValidator(min, max),
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return 0, fmt.Errorf("prompt failed: %v", err)
+	}
+
+	return parseNumber(result, defaultValue)
+}
+
+const multiSelectDone = "✔ Done"
+
+// confirmHint returns the [Y/n] style hint for a confirm prompt
+func confirmHint(defaultValue bool) string {
+	if defaultValue {
+		return "[Y/n]"
+	}
+	return "[y/N]"
+}
+
+// parseConfirm turns a yes/no answer into a bool
+func parseConfirm(input string, defaultValue bool) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "":
+		return defaultValue, nil
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	default:
+		return false, fmt.Errorf("Please answer y or n")
+	}
+}
+
+// numberValidator validates that the input is an integer within [min, max]
+func numberValidator(min, max int) func(string) error {
+	return func(input string) error {
+		if input == "" {
+			return nil
+		}
+		num, err := strconv.Atoi(input)
+		if err != nil {
+			return fmt.Errorf("Please enter a valid number")
+		}
+		if num < min || num > max {
+			return fmt.Errorf("Please enter a number between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// parseNumber converts a validated answer, using defaultValue on empty input
+func parseNumber(input string, defaultValue int) (int, error) {
+	if input == "" {
+		return defaultValue, nil
+	}
+
+	num, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %v", err)
+	}
+	return num, nil
+}
+
+// getInputTemplates returns custom templates for input prompts
+func getInputTemplates() *promptui.PromptTemplates {
+	return &promptui.PromptTemplates{
+		Prompt:          "{{ . }} ❯ ",
+		Valid:           "{{ . }} ✔ ",
+		Invalid:         "{{ . }} ✗ ",
+		Success:         "{{ . | green | bold }} ✔ ",
+		ValidationError: "{{ \"✗\" | red }} {{ . | red }}",
+	}
+}
+
+// getSelectTemplates returns custom templates for select prompts
+func getSelectTemplates() *promptui.SelectTemplates {
+	return &promptui.SelectTemplates{
+		Label:    "{{ . | bold }}?",
+		Active:   "{{ \"›\" | cyan }} {{ . | cyan | bold }}",
+		Inactive: "  {{ . | white }}",
+		Selected: "{{ \"✔\" | green }} {{ . | green | bold }}",
+		Details: `
+{{ "──────────────────" | faint }}
+{{ "Selected:" | faint }}  {{ . }}
+{{ "──────────────────" | faint }}`,
+	}
+}
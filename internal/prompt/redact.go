@@ -0,0 +1,59 @@
+package prompt
+
+import (
+	"strings"
+	"sync"
+)
+
+const redactedPlaceholder = "******"
+
+// minSecretLength is the shortest value RegisterSecret redacts. Shorter
+// values, such as a "root" user name, also appear in paths and port numbers,
+// and replacing them would mangle unrelated output. Passwords are redacted
+// whatever their length, see RegisterPassword.
+const minSecretLength = 6
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// RegisterSecret marks a value as sensitive so that it is never printed
+// verbatim by any of the output helpers in this package. Values shorter
+// than minSecretLength are ignored.
+func RegisterSecret(secret string) {
+	if len(secret) < minSecretLength {
+		return
+	}
+	register(secret)
+}
+
+// RegisterPassword marks a password as sensitive. Unlike RegisterSecret it
+// redacts short passwords too, since the user explicitly entered a secret.
+func RegisterPassword(password string) {
+	if password == "" {
+		return
+	}
+	register(password)
+}
+
+func register(secret string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+}
+
+// Redact replaces every registered secret in message with a placeholder
+func Redact(message string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, s := range secrets {
+		message = strings.ReplaceAll(message, s, redactedPlaceholder)
+	}
+	return message
+}
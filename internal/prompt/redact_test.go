@@ -0,0 +1,46 @@
+package prompt
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	defer func(saved []string) { secrets = saved }(secrets)
+	secrets = nil
+
+	RegisterSecret("s3cr3t-pass")
+	RegisterSecret("s3cr3t-pass")
+	RegisterSecret("root")
+	RegisterSecret("")
+	if len(secrets) != 1 {
+		t.Fatalf("registered %q, want only the long secret once", secrets)
+	}
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"DB_PASSWORD=s3cr3t-pass", "DB_PASSWORD=******"},
+		{"s3cr3t-pass and s3cr3t-pass", "****** and ******"},
+		{"DB_USERNAME=root in /root/project", "DB_USERNAME=root in /root/project"},
+		{"nothing to hide", "nothing to hide"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.message); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterPassword(t *testing.T) {
+	defer func(saved []string) { secrets = saved }(secrets)
+	secrets = nil
+
+	RegisterPassword("root")
+	RegisterPassword("root")
+	RegisterPassword("")
+	if len(secrets) != 1 {
+		t.Fatalf("registered %q, want the short password once", secrets)
+	}
+	if got, want := Redact("DB_PASSWORD=root"), "DB_PASSWORD=******"; got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
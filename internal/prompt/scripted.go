@@ -0,0 +1,140 @@
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ScriptedPrompter answers prompts from a fixed set of answers keyed by label.
+// It is used for unattended runs and for exercising interactive flows in tests.
+type ScriptedPrompter struct {
+	answers map[string]string
+}
+
+// NewScriptedPrompter creates a prompter that answers from the given map
+func NewScriptedPrompter(answers map[string]string) *ScriptedPrompter {
+	if answers == nil {
+		answers = map[string]string{}
+	}
+	return &ScriptedPrompter{answers: answers}
+}
+
+// NewScriptedPrompterFromFile loads answers from a JSON object of label/answer pairs
+func NewScriptedPrompterFromFile(path string) (*ScriptedPrompter, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %v", err)
+	}
+
+	var answers map[string]string
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("failed to parse answers file %s: %v", path, err)
+	}
+	return NewScriptedPrompter(answers), nil
+}
+
+// Input returns the scripted answer or defaultValue when none is given
+func (p *ScriptedPrompter) Input(label, defaultValue string, validate func(string) error) (string, error) {
+	answer, ok := p.answers[label]
+	if !ok {
+		answer = defaultValue
+	}
+	if validate != nil {
+		if err := validate(answer); err != nil {
+			return "", fmt.Errorf("invalid answer for %q: %v", label, err)
+		}
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// Password returns the scripted secret, which must be present
+func (p *ScriptedPrompter) Password(label string) (string, error) {
+	answer, ok := p.answers[label]
+	if !ok {
+		return "", fmt.Errorf("no scripted answer for %q", label)
+	}
+	return answer, nil
+}
+
+// Select matches the scripted answer against the options by value or 1-based index
+func (p *ScriptedPrompter) Select(label string, options []string) (int, string, error) {
+	answer, ok := p.answers[label]
+	if !ok {
+		return 0, "", fmt.Errorf("no scripted answer for %q", label)
+	}
+
+	index, err := matchOption(answer, options)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid answer for %q: %v", label, err)
+	}
+	return index, options[index], nil
+}
+
+// MultiSelect takes a comma separated list of options, or defaults when none is given
+func (p *ScriptedPrompter) MultiSelect(label string, options []string, defaults []string) ([]string, error) {
+	answer, ok := p.answers[label]
+	if !ok {
+		return defaults, nil
+	}
+
+	selected, err := matchOptions(answer, options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid answer for %q: %v", label, err)
+	}
+	return selected, nil
+}
+
+// Confirm returns the scripted yes/no answer or defaultValue
+func (p *ScriptedPrompter) Confirm(label string, defaultValue bool) (bool, error) {
+	result, err := parseConfirm(p.answers[label], defaultValue)
+	if err != nil {
+		return false, fmt.Errorf("invalid answer for %q: %v", label, err)
+	}
+	return result, nil
+}
+
+// Number returns the scripted number or defaultValue
+func (p *ScriptedPrompter) Number(label string, defaultValue, min, max int) (int, error) {
+	answer := p.answers[label]
+	if err := numberValidator(min, max)(answer); err != nil {
+		return 0, fmt.Errorf("invalid answer for %q: %v", label, err)
+	}
+	return parseNumber(answer, defaultValue)
+}
+
+// matchOption resolves an answer to an option index, accepting either the
+// option text or its 1-based position
+func matchOption(answer string, options []string) (int, error) {
+	answer = strings.TrimSpace(answer)
+	for i, o := range options {
+		if o == answer {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("%q is not one of %s", answer, strings.Join(options, ", "))
+}
+
+// matchOptions resolves a comma separated answer to a list of options
+func matchOptions(answer string, options []string) ([]string, error) {
+	var selected []string
+	for _, part := range strings.Split(answer, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		index, err := matchOption(part, options)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, options[index])
+	}
+	return selected, nil
+}
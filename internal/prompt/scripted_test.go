@@ -0,0 +1,109 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeAnswers(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "answers.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestScriptedPrompterFromFile(t *testing.T) {
+	p, err := NewScriptedPrompterFromFile(writeAnswers(t, `{
+		"Database name": "mine",
+		"Database password": "secret",
+		"Database type": "2",
+		"Components": "redis, mysql",
+		"Write this configuration": "n",
+		"Redis database number": "3"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := p.Input("Database name", "mineadmin", nil); err != nil || got != "mine" {
+		t.Errorf("Input = %q, %v", got, err)
+	}
+	if got, err := p.Input("Database host", "127.0.0.1", nil); err != nil || got != "127.0.0.1" {
+		t.Errorf("Input without answer = %q, %v, want the default", got, err)
+	}
+	if got, err := p.Password("Database password"); err != nil || got != "secret" {
+		t.Errorf("Password = %q, %v", got, err)
+	}
+	if i, got, err := p.Select("Database type", []string{"mysql", "pgsql"}); err != nil || i != 1 || got != "pgsql" {
+		t.Errorf("Select = %d, %q, %v", i, got, err)
+	}
+	if got, err := p.MultiSelect("Components", []string{"mysql", "redis"}, nil); err != nil || !reflect.DeepEqual(got, []string{"redis", "mysql"}) {
+		t.Errorf("MultiSelect = %q, %v", got, err)
+	}
+	if got, err := p.MultiSelect("Extras", []string{"a", "b"}, []string{"a"}); err != nil || !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("MultiSelect without answer = %q, %v, want the defaults", got, err)
+	}
+	if got, err := p.Confirm("Write this configuration", true); err != nil || got {
+		t.Errorf("Confirm = %v, %v", got, err)
+	}
+	if got, err := p.Number("Redis database number", 0, 0, 15); err != nil || got != 3 {
+		t.Errorf("Number = %d, %v", got, err)
+	}
+}
+
+func TestScriptedPrompterMissingAnswer(t *testing.T) {
+	p := NewScriptedPrompter(nil)
+	if _, err := p.Password("Database password"); err == nil {
+		t.Error("Password without answer should fail")
+	}
+	if _, _, err := p.Select("Database type", []string{"mysql"}); err == nil {
+		t.Error("Select without answer should fail")
+	}
+}
+
+func TestScriptedPrompterInvalidAnswer(t *testing.T) {
+	p := NewScriptedPrompter(map[string]string{
+		"Port":            "http",
+		"Database type":   "oracle",
+		"Continue":        "maybe",
+		"Redis database":  "99",
+		"Required answer": "",
+	})
+
+	if _, err := p.Input("Port", "", func(string) error { return errors.New("not a port") }); err == nil || !strings.Contains(err.Error(), `"Port"`) {
+		t.Errorf("Input error = %v, want one naming the label", err)
+	}
+	if _, _, err := p.Select("Database type", []string{"mysql", "pgsql"}); err == nil {
+		t.Error("Select with an unknown option should fail")
+	}
+	if _, err := p.Confirm("Continue", true); err == nil {
+		t.Error("Confirm with a non yes/no answer should fail")
+	}
+	if _, err := p.Number("Redis database", 0, 0, 15); err == nil {
+		t.Error("Number out of range should fail")
+	}
+}
+
+func TestScriptedPrompterFromFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"wrong type": `{"Server port": 9501}`,
+		"not json":   `Server port: 9501`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewScriptedPrompterFromFile(writeAnswers(t, content)); err == nil {
+				t.Error("expected a parse error")
+			}
+		})
+	}
+
+	if _, err := NewScriptedPrompterFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,174 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+)
+
+// Task is a named unit of work rendered as a spinner. Tasks started while
+// another task is running become its children and are rendered as a tree.
+// Only the innermost task spins; prompts, messages and subprocess output
+// pause it while they use the terminal.
+type Task struct {
+	title    string
+	depth    int
+	started  time.Time
+	spinner  *spinner.Spinner
+	expanded bool
+	ended    bool
+}
+
+var (
+	taskMu    sync.Mutex
+	tasks     []*Task
+	suspended int
+)
+
+// StartTask starts a task, nested under the currently running one if any
+func StartTask(title string) *Task {
+	writeLog("STEP", title)
+
+	taskMu.Lock()
+	defer taskMu.Unlock()
+
+	t := &Task{title: title, depth: len(tasks), started: time.Now()}
+	if parent := topTask(); parent != nil {
+		parent.expand()
+	}
+
+	switch {
+	case animated():
+		t.spinner = spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		t.spinner.Prefix = indent(t.depth)
+		t.spinner.Suffix = " " + Redact(title)
+		t.spinner.Color("cyan")
+		if suspended == 0 {
+			t.spinner.Start()
+		}
+	case Enabled(LevelInfo):
+		// Without a terminal print one plain line per task instead of animating
+		fmt.Fprintf(color.Output, "%s• %s\n", indent(t.depth), Redact(title))
+	}
+
+	tasks = append(tasks, t)
+	return t
+}
+
+// StartSpinner starts a task with the given message
+// Returns a task that should be stopped with Stop()
+func StartSpinner(message string) *Task {
+	return StartTask(message)
+}
+
+// Stop ends the task without printing a status line
+func (t *Task) Stop() {
+	t.finish("", "stopped")
+}
+
+// Done ends the task and marks it as successful
+func (t *Task) Done() {
+	t.finish(color.GreenString("✔"), "done")
+}
+
+// Fail ends the task and marks it as failed
+func (t *Task) Fail() {
+	t.finish(color.RedString("✗"), "failed")
+}
+
+// finish stops t and any unfinished children, then resumes its parent
+func (t *Task) finish(symbol, status string) {
+	taskMu.Lock()
+	defer taskMu.Unlock()
+
+	if t.ended {
+		return
+	}
+
+	index := len(tasks)
+	for i, running := range tasks {
+		if running == t {
+			index = i
+			break
+		}
+	}
+	for i := len(tasks) - 1; i >= index; i-- {
+		tasks[i].ended = true
+		if tasks[i].spinner != nil {
+			tasks[i].spinner.Stop()
+		}
+	}
+	if index < len(tasks) {
+		tasks = tasks[:index]
+	}
+
+	elapsed := time.Since(t.started).Round(100 * time.Millisecond)
+	writeLog("STEP", fmt.Sprintf("%s: %s (%s)", status, t.title, elapsed))
+	if symbol != "" && Enabled(LevelInfo) {
+		fmt.Fprintf(color.Output, "%s%s %s %s\n", indent(t.depth), symbol, Redact(t.title),
+			color.New(color.Faint).Sprintf("(%s)", elapsed))
+	}
+
+	if parent := topTask(); parent != nil && parent.spinner != nil && suspended == 0 {
+		parent.spinner.Start()
+	}
+}
+
+// expand prints the task as a static tree node so children render below it.
+// Must be called with taskMu held.
+func (t *Task) expand() {
+	if t.spinner != nil {
+		t.spinner.Stop()
+	}
+	if t.expanded || t.spinner == nil {
+		return
+	}
+	t.expanded = true
+	fmt.Fprintf(color.Output, "%s%s %s\n", indent(t.depth), color.CyanString("▸"), Redact(t.title))
+}
+
+// Suspend pauses the running spinner so the caller can use the terminal.
+// The returned function resumes it; suspensions may be nested.
+func Suspend() func() {
+	taskMu.Lock()
+	suspended++
+	if t := topTask(); t != nil && t.spinner != nil {
+		t.spinner.Stop()
+	}
+	taskMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			taskMu.Lock()
+			defer taskMu.Unlock()
+			suspended--
+			if t := topTask(); t != nil && t.spinner != nil && suspended == 0 {
+				t.spinner.Start()
+			}
+		})
+	}
+}
+
+// topTask returns the innermost running task. Must be called with taskMu held.
+func topTask() *Task {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return tasks[len(tasks)-1]
+}
+
+// animated reports whether spinners should be drawn
+func animated() bool {
+	return Enabled(LevelInfo) && IsTerminal(os.Stdout)
+}
+
+// indent returns the tree indentation for a nesting depth
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// PlatformNone is the platform of languages whose runtime needs no choice
+const PlatformNone = "none"
+
+// Language is a language MineAdmin projects can be created in
+type Language struct {
+	Name        string
+	Description string
+	// Platforms are the runtime platforms the language supports, the first being the default
+	Platforms []string
+}
+
+// DefaultPlatform returns the platform used when none is given
+func (l Language) DefaultPlatform() string {
+	return l.Platforms[0]
+}
+
+// languages are the supported languages and their platform combinations
+var languages = []Language{
+	{Name: "php", Description: "Hyperf backend", Platforms: []string{"swow", "swoole"}},
+	{Name: "go", Description: "Go backend", Platforms: []string{PlatformNone}},
+	{Name: "js", Description: "Node.js backend", Platforms: []string{PlatformNone}},
+}
+
+// LanguageNames returns the supported language names, sorted
+func LanguageNames() []string {
+	names := make([]string, 0, len(languages))
+	for _, l := range languages {
+		names = append(names, l.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PlatformSummary describes the platforms of every language, e.g. for flag help
+func PlatformSummary() string {
+	parts := make([]string, 0, len(languages))
+	for _, l := range languages {
+		parts = append(parts, l.Name+": "+strings.Join(l.Platforms, "/"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetLanguage returns the language called name or an error suggesting the closest one
+func GetLanguage(name string) (Language, error) {
+	for _, l := range languages {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return Language{}, utils.UnknownChoice("language", name, LanguageNames())
+}
+
+// ResolvePlatform checks platform against the language's platforms, returning
+// the default platform when it is empty
+func (l Language) ResolvePlatform(platform string) (string, error) {
+	if platform == "" {
+		return l.DefaultPlatform(), nil
+	}
+	for _, p := range l.Platforms {
+		if p == platform {
+			return platform, nil
+		}
+	}
+	return "", utils.UnknownChoice(l.Name+" platform", platform, l.Platforms)
+}
@@ -0,0 +1,56 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// gitee reads from gitee.com or a Gitee enterprise server
+type gitee struct {
+	client
+	baseURL string
+	repo    string
+}
+
+func newGitee(baseURL, repo, token string) *gitee {
+	if baseURL == "" {
+		baseURL = "https://gitee.com"
+	}
+	s := &gitee{baseURL: baseURL, repo: repo}
+	if token != "" {
+		// Gitee takes the token as a query parameter
+		s.authorize = func(req *http.Request) {
+			q := req.URL.Query()
+			q.Set("access_token", token)
+			req.URL.RawQuery = q.Encode()
+		}
+	}
+	return s
+}
+
+func (s *gitee) Name() string {
+	return s.baseURL + "/" + s.repo
+}
+
+func (s *gitee) ListReleases(ctx context.Context) ([]string, error) {
+	// Gitee lists releases oldest first unless asked otherwise
+	return s.getReleaseTags(ctx, fmt.Sprintf("%s/api/v5/repos/%s/releases?per_page=100&direction=desc", s.baseURL, s.repo))
+}
+
+func (s *gitee) ArchiveURL(ref string) string {
+	return fmt.Sprintf("%s/%s/repository/archive/%s.zip", s.baseURL, s.repo, ref)
+}
+
+func (s *gitee) Archive(ctx context.Context, ref string) (io.ReadCloser, error) {
+	return s.openArchive(ctx, s.ArchiveURL(ref))
+}
+
+func (s *gitee) FileURL(ref, path string) string {
+	return fmt.Sprintf("%s/%s/raw/%s/%s", s.baseURL, s.repo, ref, path)
+}
+
+func (s *gitee) File(ctx context.Context, ref, path string) ([]byte, error) {
+	return s.getBody(ctx, s.FileURL(ref, path))
+}
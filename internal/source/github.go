@@ -0,0 +1,69 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// gitHub reads from github.com or a GitHub Enterprise server
+// Without a token archives and files come from the web and raw hosts, which
+// are not rate limited. With a token they come from the API, the only
+// endpoints that accept it for private repositories.
+type gitHub struct {
+	client
+	webURL string
+	apiURL string
+	rawURL string
+	repo   string
+	useAPI bool
+}
+
+func newGitHub(baseURL, repo, token string) *gitHub {
+	s := &gitHub{
+		client: client{authorize: bearer(token)},
+		webURL: "https://github.com",
+		apiURL: "https://api.github.com",
+		rawURL: "https://raw.githubusercontent.com/" + repo,
+		repo:   repo,
+		useAPI: token != "",
+	}
+	if baseURL != "" {
+		// GitHub Enterprise serves the API below /api/v3 and raw files below the repository
+		s.webURL = baseURL
+		s.apiURL = baseURL + "/api/v3"
+		s.rawURL = baseURL + "/" + repo + "/raw"
+	}
+	return s
+}
+
+func (s *gitHub) Name() string {
+	return s.webURL + "/" + s.repo
+}
+
+func (s *gitHub) ListReleases(ctx context.Context) ([]string, error) {
+	return s.getReleaseTags(ctx, fmt.Sprintf("%s/repos/%s/releases?per_page=100", s.apiURL, s.repo))
+}
+
+func (s *gitHub) ArchiveURL(ref string) string {
+	if s.useAPI {
+		return fmt.Sprintf("%s/repos/%s/zipball/%s", s.apiURL, s.repo, ref)
+	}
+	return fmt.Sprintf("%s/%s/archive/%s.zip", s.webURL, s.repo, ref)
+}
+
+func (s *gitHub) Archive(ctx context.Context, ref string) (io.ReadCloser, error) {
+	return s.openArchive(ctx, s.ArchiveURL(ref))
+}
+
+func (s *gitHub) FileURL(ref, path string) string {
+	if s.useAPI {
+		return fmt.Sprintf("%s/repos/%s/contents/%s?ref=%s", s.apiURL, s.repo, path, url.QueryEscape(ref))
+	}
+	return fmt.Sprintf("%s/%s/%s", s.rawURL, ref, path)
+}
+
+func (s *gitHub) File(ctx context.Context, ref, path string) ([]byte, error) {
+	return s.getBody(ctx, s.FileURL(ref, path), "Accept", "application/vnd.github.raw")
+}
@@ -0,0 +1,51 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// gitLab reads from gitlab.com or a self-hosted GitLab through the v4 API
+type gitLab struct {
+	client
+	baseURL string
+	repo    string
+}
+
+func newGitLab(baseURL, repo, token string) *gitLab {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	return &gitLab{client: client{authorize: header("PRIVATE-TOKEN", token, token)}, baseURL: baseURL, repo: repo}
+}
+
+// projectURL is the API URL of the project, addressed by its escaped path
+func (s *gitLab) projectURL() string {
+	return fmt.Sprintf("%s/api/v4/projects/%s", s.baseURL, url.PathEscape(s.repo))
+}
+
+func (s *gitLab) Name() string {
+	return s.baseURL + "/" + s.repo
+}
+
+func (s *gitLab) ListReleases(ctx context.Context) ([]string, error) {
+	return s.getReleaseTags(ctx, s.projectURL()+"/releases?per_page=100")
+}
+
+func (s *gitLab) ArchiveURL(ref string) string {
+	return fmt.Sprintf("%s/repository/archive.zip?sha=%s", s.projectURL(), url.QueryEscape(ref))
+}
+
+func (s *gitLab) Archive(ctx context.Context, ref string) (io.ReadCloser, error) {
+	return s.openArchive(ctx, s.ArchiveURL(ref))
+}
+
+func (s *gitLab) FileURL(ref, path string) string {
+	return fmt.Sprintf("%s/repository/files/%s/raw?ref=%s", s.projectURL(), url.PathEscape(path), url.QueryEscape(ref))
+}
+
+func (s *gitLab) File(ctx context.Context, ref, path string) ([]byte, error) {
+	return s.getBody(ctx, s.FileURL(ref, path))
+}
@@ -0,0 +1,52 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// httpIndex reads from a plain HTTP server laid out as
+//
+//	<url>/index.json        {"versions": ["v3.0.1", ...]}, newest first
+//	<url>/<ref>.zip         archive with a single top-level directory
+//	<url>/<ref>/<path>      individual files
+type httpIndex struct {
+	client
+	baseURL string
+}
+
+func (s *httpIndex) Name() string {
+	return s.baseURL
+}
+
+func (s *httpIndex) ListReleases(ctx context.Context) ([]string, error) {
+	data, err := s.getBody(ctx, s.baseURL+"/index.json")
+	if err != nil {
+		return nil, err
+	}
+	var index struct {
+		Versions []string `json:"versions"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index.json: %v", err)
+	}
+	return index.Versions, nil
+}
+
+func (s *httpIndex) ArchiveURL(ref string) string {
+	return fmt.Sprintf("%s/%s.zip", s.baseURL, ref)
+}
+
+func (s *httpIndex) Archive(ctx context.Context, ref string) (io.ReadCloser, error) {
+	return s.openArchive(ctx, s.ArchiveURL(ref))
+}
+
+func (s *httpIndex) FileURL(ref, path string) string {
+	return fmt.Sprintf("%s/%s/%s", s.baseURL, ref, path)
+}
+
+func (s *httpIndex) File(ctx context.Context, ref, path string) ([]byte, error) {
+	return s.getBody(ctx, s.FileURL(ref, path))
+}
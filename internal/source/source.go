@@ -0,0 +1,195 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Source types
+const (
+	TypeGitHub = "github"
+	TypeGitee  = "gitee"
+	TypeGitLab = "gitlab"
+	TypeHTTP   = "http"
+)
+
+// Source is where project releases, archives and single files come from
+type Source interface {
+	// Name describes the source in messages
+	Name() string
+	// ListReleases returns the release tags, newest first
+	ListReleases(ctx context.Context) ([]string, error)
+	// ArchiveURL returns the zip archive location of a ref
+	ArchiveURL(ref string) string
+	// Archive opens the zip archive of a ref
+	Archive(ctx context.Context, ref string) (io.ReadCloser, error)
+	// FileURL returns the location of a file at a ref
+	FileURL(ref, path string) string
+	// File fetches a file at a ref
+	File(ctx context.Context, ref, path string) ([]byte, error)
+}
+
+// Config selects and configures a source
+type Config struct {
+	// Type is github (default), gitee, gitlab or http
+	Type string `json:"type,omitempty"`
+	// URL is the base URL of a GitHub Enterprise, self-hosted GitLab or
+	// Gitee server, or of an HTTP index; empty uses the public service
+	URL string `json:"url,omitempty"`
+	// Repo is the repository as owner/name (a group path for GitLab);
+	// empty uses the project's default repository
+	Repo string `json:"repo,omitempty"`
+	// Token authenticates against private repositories; see TokenFromEnv
+	Token string `json:"token,omitempty"`
+}
+
+// EnvToken is the token used for any source type when the config has none
+const EnvToken = "MINE_SOURCE_TOKEN"
+
+// tokenEnvs are the conventional token variables per source type
+var tokenEnvs = map[string][]string{
+	TypeGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	TypeGitee:  {"GITEE_TOKEN"},
+	TypeGitLab: {"GITLAB_TOKEN"},
+}
+
+// ResolveToken returns the configured token, else $MINE_SOURCE_TOKEN, else
+// the conventional variable of the source type such as $GITHUB_TOKEN
+func (c Config) ResolveToken() string {
+	if c.Token != "" {
+		return c.Token
+	}
+	envs := append([]string{EnvToken}, tokenEnvs[c.sourceType()]...)
+	for _, env := range envs {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+func (c Config) sourceType() string {
+	if c.Type == "" {
+		return TypeGitHub
+	}
+	return c.Type
+}
+
+// New creates the source described by cfg for a project whose default
+// repository is defaultRepo
+func New(cfg Config, defaultRepo string) (Source, error) {
+	repo := cfg.Repo
+	if repo == "" {
+		repo = defaultRepo
+	}
+	baseURL := strings.TrimRight(cfg.URL, "/")
+	token := cfg.ResolveToken()
+
+	switch cfg.sourceType() {
+	case TypeGitHub:
+		return newGitHub(baseURL, repo, token), nil
+	case TypeGitee:
+		return newGitee(baseURL, repo, token), nil
+	case TypeGitLab:
+		return newGitLab(baseURL, repo, token), nil
+	case TypeHTTP:
+		if baseURL == "" {
+			return nil, fmt.Errorf("source type http needs a url")
+		}
+		return &httpIndex{baseURL: baseURL, client: client{authorize: bearer(token)}}, nil
+	}
+	return nil, fmt.Errorf("unknown source type %q, expected one of %s, %s, %s or %s", cfg.Type, TypeGitHub, TypeGitee, TypeGitLab, TypeHTTP)
+}
+
+// client performs the source's HTTP requests, authenticating them when a token is set
+type client struct {
+	authorize func(req *http.Request)
+}
+
+// bearer authorizes requests with an Authorization: Bearer header
+func bearer(token string) func(req *http.Request) {
+	return header("Authorization", "Bearer "+token, token)
+}
+
+// header authorizes requests by setting name to value when token is set
+func header(name, value, token string) func(req *http.Request) {
+	if token == "" {
+		return nil
+	}
+	return func(req *http.Request) {
+		req.Header.Set(name, value)
+	}
+}
+
+// get requests url and fails on anything but 200 OK
+func (c client) get(ctx context.Context, url string, headers ...string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	if c.authorize != nil {
+		c.authorize(req)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized) && c.authorize == nil {
+			return nil, fmt.Errorf("GET %s: %s (set a token if the repository is private)", url, resp.Status)
+		}
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
+
+// getBody returns the body of url
+func (c client) getBody(ctx context.Context, url string, headers ...string) ([]byte, error) {
+	resp, err := c.get(ctx, url, headers...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// getReleaseTags reads the tag_name of every release in a JSON array, the
+// format shared by the GitHub, Gitee and GitLab release APIs
+func (c client) getReleaseTags(ctx context.Context, url string) ([]string, error) {
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var releases []struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %v", err)
+	}
+	tags := make([]string, 0, len(releases))
+	for _, r := range releases {
+		tags = append(tags, r.TagName)
+	}
+	return tags, nil
+}
+
+// openArchive opens an archive URL for reading
+func (c client) openArchive(ctx context.Context, url string) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
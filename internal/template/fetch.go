@@ -0,0 +1,210 @@
+package template
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mineadmin/mine/internal/utils"
+)
+
+// Kinds of template locations
+const (
+	KindDir = "directory"
+	KindZip = "zip"
+	KindGit = "git"
+)
+
+// Kind tells how a template location is fetched: an existing directory, a
+// zip file or URL, or a git repository. A git URL may end in #<ref> to
+// clone a branch or tag.
+func Kind(location string) (string, error) {
+	remote := isRemote(location)
+	if !remote {
+		info, err := os.Stat(location)
+		if err != nil {
+			return "", fmt.Errorf("template %s not found", location)
+		}
+		if info.IsDir() {
+			return KindDir, nil
+		}
+	}
+	if strings.HasSuffix(strings.ToLower(location), ".zip") {
+		return KindZip, nil
+	}
+	if remote {
+		return KindGit, nil
+	}
+	return "", fmt.Errorf("template %s is neither a directory, a zip file nor a git repository", location)
+}
+
+// isRemote reports whether location is a URL or scp-style git address
+func isRemote(location string) bool {
+	for _, scheme := range []string{"http://", "https://", "git://", "ssh://", "git@"} {
+		if strings.HasPrefix(location, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch makes the template at location available on disk and loads it. The
+// returned cleanup removes anything Fetch downloaded.
+func Fetch(ctx context.Context, location string) (*Template, func(), error) {
+	kind, err := Kind(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind == KindDir {
+		t, err := Load(location)
+		return t, func() {}, err
+	}
+
+	dir, err := ioutil.TempDir("", "mine-template-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	root := dir
+	if kind == KindGit {
+		err = clone(ctx, location, dir)
+	} else {
+		root, err = fetchZip(ctx, location, dir)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	t, err := Load(root)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return t, cleanup, nil
+}
+
+// clone checks out a shallow copy of the repository into dir
+func clone(ctx context.Context, location, dir string) error {
+	args := []string{"clone", "--depth", "1"}
+	if url, ref, ok := strings.Cut(location, "#"); ok {
+		location = url
+		args = append(args, "--branch", ref)
+	}
+	if !utils.CheckCommandExists("git") {
+		return fmt.Errorf("git is required to use template %s", location)
+	}
+	cmd := utils.Command{Name: "git", Args: append(args, location, dir), Env: []string{"GIT_TERMINAL_PROMPT=0"}}
+	if _, err := utils.Runner().Output(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to clone template: %v", err)
+	}
+	return nil
+}
+
+// fetchZip downloads (when remote) and extracts a zip template into dir and
+// returns the template root. Archives that wrap everything in a single
+// top-level directory, as GitHub archives do, are rooted at that directory.
+func fetchZip(ctx context.Context, location, dir string) (string, error) {
+	archive := location
+	if isRemote(location) {
+		archive = filepath.Join(dir, "template.zip")
+		if err := download(ctx, location, archive); err != nil {
+			return "", err
+		}
+		defer os.Remove(archive)
+	}
+
+	root := filepath.Join(dir, "template")
+	if err := extract(ctx, archive, root); err != nil {
+		return "", fmt.Errorf("failed to unzip template: %v", err)
+	}
+
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		if _, err := os.Stat(filepath.Join(root, ManifestFile)); os.IsNotExist(err) {
+			return filepath.Join(root, entries[0].Name()), nil
+		}
+	}
+	return root, nil
+}
+
+// download saves the body of url to path
+func download(ctx context.Context, url, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download template: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download template: %s returned %s", url, resp.Status)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+// extract writes every file of the archive below dest
+func extract(ctx context.Context, archive, dest string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		path := filepath.Join(dest, filepath.FromSlash(f.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dest)+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s escapes the template directory", f.Name)
+		}
+		if err := extractFile(f, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractFile writes a single archive entry to path
+func extractFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm()|0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
+}
@@ -0,0 +1,243 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	gotemplate "text/template"
+
+	"github.com/mineadmin/mine/internal/composer"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the template description at the root of a template
+const ManifestFile = "template.yaml"
+
+// RenderSuffix marks files that are rendered with text/template; the suffix
+// is dropped from the written file name
+const RenderSuffix = ".tmpl"
+
+// Manifest is the contents of template.yaml
+type Manifest struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Prompts     []Prompt `yaml:"prompts"`
+	// Composer maps a composer.json section to package constraints; an empty
+	// constraint removes the package
+	Composer map[string]map[string]string `yaml:"composer"`
+	// Env holds .env keys to set; values are rendered like template files
+	Env       map[string]string `yaml:"env"`
+	PostSteps []PostStep        `yaml:"post_steps"`
+}
+
+// Prompt is a value asked for when the template is applied and exposed to
+// templates as .Vars.<name>
+type Prompt struct {
+	Name    string   `yaml:"name"`
+	Label   string   `yaml:"label"`
+	Default string   `yaml:"default"`
+	Options []string `yaml:"options"`
+}
+
+// PostStep is a command run in the project once it is set up. Every
+// argument is rendered like template files.
+type PostStep struct {
+	Name string   `yaml:"name"`
+	Run  []string `yaml:"run"`
+}
+
+// Data is what template files, env values and post-step arguments can refer to
+type Data struct {
+	ProjectName string
+	Language    string
+	Version     string
+	Platform    string
+	AppHost     string
+	AppPort     string
+	AppURL      string
+	DBDriver    string
+	DBHost      string
+	DBPort      string
+	DBName      string
+	DBUser      string
+	RedisHost   string
+	RedisPort   string
+	RedisDB     string
+	BinPhp      string
+	BinComposer string
+	// Vars holds the answers to the manifest's prompts
+	Vars map[string]string
+}
+
+// Template is a fetched template directory and its manifest
+type Template struct {
+	Dir      string
+	Manifest Manifest
+}
+
+// Load reads the template in dir. A missing template.yaml is allowed and
+// means the template only has files.
+func Load(dir string) (*Template, error) {
+	t := &Template{Dir: dir}
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &t.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", ManifestFile, err)
+	}
+	if err := t.Manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", ManifestFile, err)
+	}
+	return t, nil
+}
+
+func (m *Manifest) validate() error {
+	seen := map[string]bool{}
+	for _, p := range m.Prompts {
+		if p.Name == "" {
+			return fmt.Errorf("prompt without a name")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate prompt %q", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	for section := range m.Composer {
+		if section != composer.SectionRequire && section != composer.SectionRequireDev {
+			return fmt.Errorf("unsupported composer section %q, expected %s or %s", section, composer.SectionRequire, composer.SectionRequireDev)
+		}
+	}
+	for i, step := range m.PostSteps {
+		if len(step.Run) == 0 {
+			return fmt.Errorf("post step %d has no command", i+1)
+		}
+	}
+	return nil
+}
+
+// Title returns the template's name for messages
+func (t *Template) Title() string {
+	if t.Manifest.Name != "" {
+		return t.Manifest.Name
+	}
+	return filepath.Base(t.Dir)
+}
+
+// PackageChanges returns the manifest's composer edits in a stable order
+func (t *Template) PackageChanges() []composer.PackageChange {
+	var changes []composer.PackageChange
+	for _, section := range []string{composer.SectionRequire, composer.SectionRequireDev} {
+		for _, pkg := range sortedKeys(t.Manifest.Composer[section]) {
+			changes = append(changes, composer.PackageChange{Section: section, Package: pkg, Version: t.Manifest.Composer[section][pkg]})
+		}
+	}
+	return changes
+}
+
+// EnvKeys returns the manifest's .env keys, sorted
+func (t *Template) EnvKeys() []string {
+	return sortedKeys(t.Manifest.Env)
+}
+
+// Files returns the slash separated paths of the files the template lays
+// over the project, relative to its root and before dropping RenderSuffix
+func (t *Template) Files() ([]string, error) {
+	var files []string
+	err := filepath.Walk(t.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(t.Dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() {
+			if rel == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == ManifestFile {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+// TargetPath returns where a template file is written inside the project
+func TargetPath(file string) string {
+	return strings.TrimSuffix(file, RenderSuffix)
+}
+
+// Apply lays the template's files over projectRoot, rendering the ones
+// ending in RenderSuffix with data. Existing files are overwritten.
+func (t *Template) Apply(projectRoot string, data Data) ([]string, error) {
+	files, err := t.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, file := range files {
+		src := filepath.Join(t.Dir, filepath.FromSlash(file))
+		info, err := os.Stat(src)
+		if err != nil {
+			return written, err
+		}
+		content, err := ioutil.ReadFile(src)
+		if err != nil {
+			return written, err
+		}
+		if strings.HasSuffix(file, RenderSuffix) {
+			rendered, err := Render(file, string(content), data)
+			if err != nil {
+				return written, err
+			}
+			content = []byte(rendered)
+		}
+
+		target := filepath.Join(projectRoot, filepath.FromSlash(TargetPath(file)))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return written, err
+		}
+		if err := ioutil.WriteFile(target, content, info.Mode().Perm()); err != nil {
+			return written, err
+		}
+		written = append(written, TargetPath(file))
+	}
+	return written, nil
+}
+
+// Render executes text with data. Unknown fields and prompts are errors, so a
+// typo does not silently render as an empty string.
+func Render(name, text string, data Data) (string, error) {
+	tmpl, err := gotemplate.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", name, err)
+	}
+	return buf.String(), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
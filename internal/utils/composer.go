@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ComposerMirrors maps mirror presets to their repository URLs
+var ComposerMirrors = map[string]string{
+	"aliyun":  "https://mirrors.aliyun.com/composer/",
+	"tencent": "https://mirrors.tencent.com/composer/",
+	"huawei":  "https://repo.huaweicloud.com/repository/php/",
+}
+
+// Composer mirror modes
+const (
+	MirrorTemporary = "temporary"
+	MirrorPermanent = "permanent"
+)
+
+// ResolveComposerMirror turns a preset name or URL into a repository URL
+func ResolveComposerMirror(mirror string) (string, error) {
+	if u, ok := ComposerMirrors[mirror]; ok {
+		return u, nil
+	}
+
+	u, err := url.Parse(mirror)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		names := make([]string, 0, len(ComposerMirrors))
+		for name := range ComposerMirrors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown composer mirror %q, expected one of %s or an http(s) URL", mirror, strings.Join(names, ", "))
+	}
+	return mirror, nil
+}
+
+// ValidateMirrorMode checks a composer mirror mode
+func ValidateMirrorMode(mode string) error {
+	if mode != MirrorTemporary && mode != MirrorPermanent {
+		return fmt.Errorf("invalid composer mirror mode %q, expected %s or %s", mode, MirrorTemporary, MirrorPermanent)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ReadEnvFile parses KEY=VALUE lines of a dotenv file, ignoring comments
+func ReadEnvFile(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		key, value, ok := parseEnvLine(scanner.Text())
+		if ok {
+			values[key] = value
+		}
+	}
+	return values, scanner.Err()
+}
+
+// SetEnvValues updates keys in a dotenv file in place, keeping every other
+// line, and appends keys that are not present yet. The file is created if missing.
+func SetEnvValues(path string, values map[string]string, order []string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	seen := map[string]bool{}
+	for i, line := range lines {
+		key, _, ok := parseEnvLine(line)
+		if !ok {
+			continue
+		}
+		if value, found := values[key]; found {
+			lines[i] = fmt.Sprintf("%s%s", envLinePrefix(line), value)
+			seen[key] = true
+		}
+	}
+	for _, key := range order {
+		if _, found := values[key]; found && !seen[key] {
+			lines = append(lines, fmt.Sprintf("%s=%s", key, values[key]))
+		}
+	}
+
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// parseEnvLine splits a KEY=VALUE line, trimming quotes and surrounding spaces
+func parseEnvLine(line string) (string, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", "", false
+	}
+	key, value, ok := strings.Cut(trimmed, "=")
+	if !ok {
+		return "", "", false
+	}
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, " #"); i >= 0 && !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+		value = strings.TrimSpace(value[:i])
+	}
+	return strings.TrimSpace(key), strings.Trim(value, `"'`), true
+}
+
+// envLinePrefix returns the "KEY =" part of a line so its spacing is kept
+func envLinePrefix(line string) string {
+	i := strings.Index(line, "=")
+	prefix := line[:i+1]
+	rest := line[i+1:]
+	return prefix + rest[:len(rest)-len(strings.TrimLeft(rest, " "))]
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NpmRegistries maps npm registry mirror presets to their URLs
+var NpmRegistries = map[string]string{
+	"npmmirror": "https://registry.npmmirror.com/",
+	"tencent":   "https://mirrors.cloud.tencent.com/npm/",
+	"huawei":    "https://repo.huaweicloud.com/repository/npm/",
+}
+
+// ResolveNpmRegistry turns a preset name or URL into a registry URL
+func ResolveNpmRegistry(registry string) (string, error) {
+	if u, ok := NpmRegistries[registry]; ok {
+		return u, nil
+	}
+
+	u, err := url.Parse(registry)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		names := make([]string, 0, len(NpmRegistries))
+		for name := range NpmRegistries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown npm registry %q, expected one of %s or an http(s) URL", registry, strings.Join(names, ", "))
+	}
+	return registry, nil
+}
+
+// NpmRegistryEnv returns the environment that points npm, pnpm and yarn at a registry
+func NpmRegistryEnv(registry string) []string {
+	if registry == "" {
+		return nil
+	}
+	return []string{
+		"NPM_CONFIG_REGISTRY=" + registry,
+		"YARN_NPM_REGISTRY_SERVER=" + registry,
+	}
+}
+
+// packageJSON holds the parts of package.json the CLI looks at
+type packageJSON struct {
+	PackageManager string `json:"packageManager"`
+	Engines        struct {
+		Node string `json:"node"`
+	} `json:"engines"`
+}
+
+func readPackageJSON(dir string) (*packageJSON, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %v", err)
+	}
+	return &pkg, nil
+}
+
+// DetectPackageManager picks pnpm, yarn or npm from the lock file in dir,
+// then from the packageManager field of package.json, defaulting to npm
+func DetectPackageManager(dir string) string {
+	lockFiles := []struct {
+		file    string
+		manager string
+	}{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"package-lock.json", "npm"},
+	}
+	for _, l := range lockFiles {
+		if _, err := os.Stat(filepath.Join(dir, l.file)); err == nil {
+			return l.manager
+		}
+	}
+
+	if pkg, err := readPackageJSON(dir); err == nil && pkg.PackageManager != "" {
+		name, _, _ := strings.Cut(pkg.PackageManager, "@")
+		switch name {
+		case "pnpm", "yarn", "npm":
+			return name
+		}
+	}
+	return "npm"
+}
+
+var majorVersion = regexp.MustCompile(`(\d+)`)
+
+// RequiredNodeMajor returns the minimum Node major version from package.json
+// engines.node, or fallback when none is declared
+func RequiredNodeMajor(dir string, fallback int) int {
+	pkg, err := readPackageJSON(dir)
+	if err != nil || pkg.Engines.Node == "" {
+		return fallback
+	}
+	if m := majorVersion.FindString(pkg.Engines.Node); m != "" {
+		if n, err := strconv.Atoi(m); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// NodeMajorVersion returns the major version of the node binary
+func NodeMajorVersion(ctx context.Context, nodeBin string) (int, error) {
+	output, err := Runner().Output(ctx, Command{Name: nodeBin, Args: []string{"--version"}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to check Node version: %v", err)
+	}
+	m := majorVersion.FindString(string(output))
+	if m == "" {
+		return 0, fmt.Errorf("unexpected node --version output %q", strings.TrimSpace(string(output)))
+	}
+	return strconv.Atoi(m)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package utils
+
+import (
+	"os/exec"
+)
+
+// configureProcessGroup keeps the default cancellation on Windows, which
+// kills the command's process when the context is cancelled
+func configureProcessGroup(cmd *exec.Cmd) {}
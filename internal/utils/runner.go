@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mineadmin/mine/internal/prompt"
+)
+
+// Command describes a subprocess to run
+type Command struct {
+	Name string
+	Args []string
+	Dir  string
+	// Env holds extra KEY=VALUE pairs added to the current environment
+	Env []string
+	// Timeout limits how long the command may run; zero means no limit
+	Timeout time.Duration
+}
+
+// String returns the command line for messages
+func (c Command) String() string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
+
+// CommandRunner runs subprocesses. Tests replace it with SetRunner to stub commands.
+type CommandRunner interface {
+	// Run streams the command's output to the terminal and the log file
+	Run(ctx context.Context, cmd Command) error
+	// Output runs the command and returns its combined output
+	Output(ctx context.Context, cmd Command) ([]byte, error)
+}
+
+// CommandError reports a failed command together with the tail of its stderr
+type CommandError struct {
+	Command  string
+	Err      error
+	Stderr   string
+	TimedOut bool
+}
+
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Command, e.Err)
+	if e.TimedOut {
+		msg = fmt.Sprintf("%s: timed out", e.Command)
+	}
+	if e.Stderr != "" {
+		msg += "\n" + e.Stderr
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExecRunner runs commands with os/exec
+type ExecRunner struct {
+	// TailLines is how many trailing stderr lines are kept for error reports
+	TailLines int
+}
+
+// NewExecRunner creates a runner keeping the last 20 stderr lines
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{TailLines: 20}
+}
+
+// commandWaitDelay is how long a cancelled command may take to exit before it is killed
+const commandWaitDelay = 10 * time.Second
+
+// prepare builds the exec.Cmd, applying timeout, environment and process group
+func (r *ExecRunner) prepare(ctx context.Context, c Command) (*exec.Cmd, context.Context, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+	}
+
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	configureProcessGroup(cmd)
+	cmd.WaitDelay = commandWaitDelay
+
+	prompt.Debug(fmt.Sprintf("Running %s in %s", c, c.Dir))
+	// Values may be passwords, only their names are logged
+	for _, env := range c.Env {
+		key, _, _ := strings.Cut(env, "=")
+		prompt.Debug(fmt.Sprintf("  with %s", key))
+	}
+	return cmd, ctx, cancel
+}
+
+// Run streams the command's output, returning a CommandError with the tail
+// of stderr when it fails
+func (r *ExecRunner) Run(parent context.Context, c Command) error {
+	cmd, ctx, cancel := r.prepare(parent, c)
+	defer cancel()
+
+	tail := newTailWriter(r.TailLines)
+	stdout, stderr := prompt.CommandOutput()
+	cmd.Stdout = stdout
+	cmd.Stderr = io.MultiWriter(stderr, tail)
+
+	// Keep spinners off the terminal while the command streams its output
+	defer prompt.Suspend()()
+	return r.wrap(parent, ctx, c, cmd.Run(), tail.String())
+}
+
+// Output runs the command and returns its combined output
+func (r *ExecRunner) Output(parent context.Context, c Command) ([]byte, error) {
+	cmd, ctx, cancel := r.prepare(parent, c)
+	defer cancel()
+
+	output, err := cmd.CombinedOutput()
+	return output, r.wrap(parent, ctx, c, err, lastLines(string(output), r.TailLines))
+}
+
+// wrap turns a command failure into a CommandError. Cancellation of the
+// parent context is returned as is so callers can tell it from failures.
+func (r *ExecRunner) wrap(parent, ctx context.Context, c Command, err error, stderr string) error {
+	if err == nil {
+		return nil
+	}
+	if parent.Err() != nil {
+		return parent.Err()
+	}
+	return &CommandError{
+		Command:  c.String(),
+		Err:      err,
+		Stderr:   stderr,
+		TimedOut: ctx.Err() == context.DeadlineExceeded,
+	}
+}
+
+var (
+	runnerMu sync.RWMutex
+	runner   CommandRunner = NewExecRunner()
+)
+
+// SetRunner replaces the runner used by the package level command helpers
+func SetRunner(r CommandRunner) {
+	runnerMu.Lock()
+	defer runnerMu.Unlock()
+	runner = r
+}
+
+// Runner returns the runner used by the package level command helpers
+func Runner() CommandRunner {
+	runnerMu.RLock()
+	defer runnerMu.RUnlock()
+	return runner
+}
+
+// RunCommand runs a command with the current runner
+func RunCommand(ctx context.Context, cmd Command) error {
+	return Runner().Run(ctx, cmd)
+}
+
+// tailWriter keeps the last n lines written to it
+type tailWriter struct {
+	mu    sync.Mutex
+	n     int
+	lines []string
+	buf   []byte
+}
+
+func newTailWriter(n int) *tailWriter {
+	return &tailWriter{n: n}
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.push(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *tailWriter) push(line string) {
+	w.lines = append(w.lines, strings.TrimRight(line, "\r"))
+	if len(w.lines) > w.n {
+		w.lines = w.lines[len(w.lines)-w.n:]
+	}
+}
+
+// String returns the kept lines, including an unterminated last line
+func (w *tailWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lines := w.lines
+	if len(w.buf) > 0 {
+		lines = append(append([]string{}, lines...), string(w.buf))
+		if len(lines) > w.n {
+			lines = lines[len(lines)-w.n:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lastLines returns the last n non-empty-trailing lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
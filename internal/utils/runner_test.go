@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mineadmin/mine/internal/prompt"
+)
+
+func TestExecRunnerOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("MINE_TEST_BASE", "base")
+
+	tests := []struct {
+		name       string
+		script     string
+		env        []string
+		timeout    time.Duration
+		wantOutput string
+		wantErr    bool
+		timedOut   bool
+		stderr     string
+	}{
+		{
+			name:       "success",
+			script:     "echo ok",
+			wantOutput: "ok\n",
+		},
+		{
+			name:       "env is merged with the current environment",
+			script:     `echo "$MINE_TEST_BASE $MINE_TEST_EXTRA"`,
+			env:        []string{"MINE_TEST_EXTRA=extra"},
+			wantOutput: "base extra\n",
+		},
+		{
+			name:    "failure keeps the stderr tail",
+			script:  "for i in 1 2 3 4 5; do echo line$i >&2; done; exit 3",
+			wantErr: true,
+			stderr:  "line3\nline4\nline5",
+		},
+		{
+			name:     "timeout",
+			script:   "sleep 5",
+			timeout:  100 * time.Millisecond,
+			wantErr:  true,
+			timedOut: true,
+		},
+	}
+
+	r := &ExecRunner{TailLines: 3}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := r.Output(context.Background(), Command{
+				Name:    "sh",
+				Args:    []string{"-c", tt.script},
+				Env:     tt.env,
+				Timeout: tt.timeout,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(output) != tt.wantOutput {
+					t.Errorf("output = %q, want %q", output, tt.wantOutput)
+				}
+				return
+			}
+
+			var cmdErr *CommandError
+			if !errors.As(err, &cmdErr) {
+				t.Fatalf("error = %v, want a CommandError", err)
+			}
+			if cmdErr.TimedOut != tt.timedOut {
+				t.Errorf("TimedOut = %v, want %v", cmdErr.TimedOut, tt.timedOut)
+			}
+			if cmdErr.Stderr != tt.stderr {
+				t.Errorf("Stderr = %q, want %q", cmdErr.Stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestExecRunnerLogsEnvNamesOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	logPath := filepath.Join(t.TempDir(), "mine.log")
+	if err := prompt.ConfigureOutput(prompt.OutputOptions{Quiet: true, LogFile: logPath}); err != nil {
+		t.Fatal(err)
+	}
+	defer prompt.CloseOutput()
+
+	cmd := Command{Name: "sh", Args: []string{"-c", "true"}, Env: []string{"MINE_TEST_PASSWORD=hunter2-secret"}}
+	if _, err := NewExecRunner().Output(context.Background(), cmd); err != nil {
+		t.Fatal(err)
+	}
+	prompt.CloseOutput()
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "MINE_TEST_PASSWORD") {
+		t.Errorf("log does not name the variable:\n%s", log)
+	}
+	if strings.Contains(string(log), "hunter2-secret") {
+		t.Errorf("log contains the variable's value:\n%s", log)
+	}
+}
+
+// fakeRunner records commands instead of running them
+type fakeRunner struct {
+	commands []Command
+	err      error
+}
+
+func (f *fakeRunner) Run(ctx context.Context, cmd Command) error {
+	f.commands = append(f.commands, cmd)
+	return f.err
+}
+
+func (f *fakeRunner) Output(ctx context.Context, cmd Command) ([]byte, error) {
+	f.commands = append(f.commands, cmd)
+	return nil, f.err
+}
+
+func TestSetRunner(t *testing.T) {
+	fake := &fakeRunner{err: &CommandError{Command: "composer install", Err: errors.New("exit status 1"), Stderr: "memory exhausted"}}
+	previous := Runner()
+	SetRunner(fake)
+	defer SetRunner(previous)
+
+	cmd := Command{Name: "composer", Args: []string{"install"}, Timeout: time.Minute}
+	err := RunCommand(context.Background(), cmd)
+	if len(fake.commands) != 1 || fake.commands[0].String() != "composer install" || fake.commands[0].Timeout != time.Minute {
+		t.Fatalf("runner got %+v", fake.commands)
+	}
+	if want := "composer install: exit status 1\nmemory exhausted"; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
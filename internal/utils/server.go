@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+)
+
+var (
+	httpServerName = regexp.MustCompile(`['"]name['"]\s*=>\s*['"]http['"]`)
+	serverPortExpr = regexp.MustCompile(`(?m)(['"]port['"]\s*=>\s*)(.*?),?[ \t]*$`)
+	lastInteger    = regexp.MustCompile(`\d+(\D*)$`)
+)
+
+// SetServerPort changes the port of the http server in a Hyperf
+// config/autoload/server.php. Both literal ports and env() defaults such as
+// (int) env('HTTP_PORT', 9501) are updated.
+func SetServerPort(path string, port int) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	// Prefer the port following the server named "http", else the first port
+	offset := 0
+	if loc := httpServerName.FindStringIndex(content); loc != nil {
+		offset = loc[1]
+	}
+	loc := serverPortExpr.FindStringSubmatchIndex(content[offset:])
+	if loc == nil {
+		return fmt.Errorf("no server port found in %s", path)
+	}
+
+	exprStart, exprEnd := offset+loc[4], offset+loc[5]
+	expr := content[exprStart:exprEnd]
+	if !lastInteger.MatchString(expr) {
+		return fmt.Errorf("unsupported server port expression %q in %s", expr, path)
+	}
+	expr = lastInteger.ReplaceAllString(expr, strconv.Itoa(port)+"${1}")
+
+	content = content[:exprStart] + expr + content[exprEnd:]
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Suggest returns the candidate closest to value by edit distance, or an
+// empty string when none is close enough to be a likely typo
+func Suggest(value string, candidates []string) string {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		d := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	// Allow roughly one edit per three characters, at least one
+	limit := len(best) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	if bestDistance < 0 || bestDistance > limit {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// UnknownChoice builds the error for a value that is not one of valid,
+// suggesting the nearest valid value
+func UnknownChoice(kind, value string, valid []string) error {
+	if suggestion := Suggest(value, valid); suggestion != "" {
+		return fmt.Errorf("unknown %s %q, did you mean %q? (expected one of %s)", kind, value, suggestion, strings.Join(valid, ", "))
+	}
+	return fmt.Errorf("unknown %s %q, expected one of %s", kind, value, strings.Join(valid, ", "))
+}
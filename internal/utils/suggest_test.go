@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	platforms := []string{"swoole", "swow"}
+	languages := []string{"go", "javascript", "php"}
+
+	tests := []struct {
+		value      string
+		candidates []string
+		want       string
+	}{
+		{"swoole", platforms, "swoole"},
+		{"swool", platforms, "swoole"},
+		{"SWOW", platforms, "swow"},
+		{"swo", platforms, "swow"},
+		{"javscript", languages, "javascript"},
+		{"pgp", languages, "php"},
+		{"rust", languages, ""},
+		{"typescript", languages, ""},
+		{"", languages, ""},
+		{"php", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.value, tt.candidates); got != tt.want {
+			t.Errorf("Suggest(%q, %q) = %q, want %q", tt.value, tt.candidates, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownChoice(t *testing.T) {
+	err := UnknownChoice("platform", "swol", []string{"swoole", "swow"})
+	if want := `unknown platform "swol", did you mean "swow"? (expected one of swoole, swow)`; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	err = UnknownChoice("platform", "roadrunner", []string{"swoole", "swow"})
+	if strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("error = %q, want no suggestion", err)
+	}
+}
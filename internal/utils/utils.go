@@ -1,12 +1,11 @@
 package utils
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -33,6 +32,16 @@ func GenerateAppKey() (string, error) {
 	return base64.StdEncoding.EncodeToString(bytes), nil
 }
 
+// GeneratePassword generates a random URL-safe password
+func GeneratePassword() (string, error) {
+	bytes := make([]byte, 12) // 16 characters once encoded
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
 // CheckCommandExists checks if a command exists in the system
 func CheckCommandExists(command string) bool {
 	_, err := exec.LookPath(command)
@@ -90,37 +99,9 @@ func ReplaceFile(source, target string) error {
 	return ioutil.WriteFile(target, input, 0644)
 }
 
-// ModifyComposerJSON modifies composer.json to replace ext-swoole with hyperf/engine-swow
-func ModifyComposerJSON(path string) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
-	var composer map[string]interface{}
-	if err := json.Unmarshal(data, &composer); err != nil {
-		return err
-	}
-
-	// Modify requires section
-	if requires, ok := composer["require"].(map[string]interface{}); ok {
-		delete(requires, "ext-swoole")
-		requires["hyperf/engine-swow"] = "*"
-	}
-
-	// Write back to file
-	newData, err := json.MarshalIndent(composer, "", "    ")
-	if err != nil {
-		return err
-	}
-
-	return ioutil.WriteFile(path, newData, 0644)
-}
-
 // CheckPhpExtension checks if a PHP extension is loaded
-func CheckPhpExtension(phpBin, extension string) (bool, error) {
-	cmd := exec.Command(phpBin, "-m")
-	output, err := cmd.CombinedOutput()
+func CheckPhpExtension(ctx context.Context, phpBin, extension string) (bool, error) {
+	output, err := Runner().Output(ctx, Command{Name: phpBin, Args: []string{"-m"}})
 	if err != nil {
 		return false, fmt.Errorf("failed to check PHP extensions: %v", err)
 	}
@@ -134,30 +115,26 @@ func CheckPhpExtension(phpBin, extension string) (bool, error) {
 	return false, nil
 }
 
-// RunCommandWithOutput runs a command and streams its output in real-time
-func RunCommandWithOutput(command string, args []string, workingDir string) error {
-	cmd := exec.Command(command, args...)
-	cmd.Dir = workingDir
-
-	// Stream stdout and stderr to os.Stdout and os.Stderr
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// PhpIniValue returns the current value of a php.ini setting
+func PhpIniValue(ctx context.Context, phpBin, name string) (string, error) {
+	output, err := Runner().Output(ctx, Command{Name: phpBin, Args: []string{"-r", "echo ini_get($argv[1]);", name}})
+	if err != nil {
+		return "", fmt.Errorf("failed to read PHP setting %s: %v", name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
 
-	return cmd.Run()
+// RunCommandWithOutput runs a command and streams its output in real-time.
+// The command and its children are terminated when ctx is cancelled.
+func RunCommandWithOutput(ctx context.Context, command string, args []string, workingDir string) error {
+	return RunCommand(ctx, Command{Name: command, Args: args, Dir: workingDir})
 }
 
-// GetGitHubFileContent fetches file content from GitHub repository
-func GetGitHubFileContent(repo, version, filePath string) ([]byte, error) {
-	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", repo, version, filePath)
-	resp, err := http.Get(url)
+// ReadSecretFile reads a secret from a file, dropping the trailing newline
+func ReadSecretFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("failed to read secret file %s: %v", path, err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to fetch file: %s", resp.Status)
-	}
-
-	return ioutil.ReadAll(resp.Body)
+	return strings.TrimRight(string(data), "\r\n"), nil
 }
@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	mysqlIdent    = regexp.MustCompile(`^[0-9a-zA-Z$_]+$`)
+	pgsqlIdent    = regexp.MustCompile(`^[a-zA-Z_][0-9a-zA-Z$_]*$`)
+	allDigits     = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Drivers lists the supported database drivers
+var Drivers = []string{"mysql", "pgsql"}
+
+// Driver validates a database driver name
+func Driver(value string) error {
+	for _, d := range Drivers {
+		if value == d {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported database driver %q, expected one of %s", value, strings.Join(Drivers, ", "))
+}
+
+// Host validates an IPv4/IPv6 address or an RFC 1123 hostname
+func Host(value string) error {
+	if value == "" {
+		return fmt.Errorf("host cannot be empty")
+	}
+	if net.ParseIP(value) != nil {
+		return nil
+	}
+	// Brackets belong in URLs, which add them around IPv6 addresses themselves
+	if strings.HasPrefix(value, "[") && net.ParseIP(strings.Trim(value, "[]")) != nil {
+		return fmt.Errorf("write the IPv6 address %s without brackets", strings.Trim(value, "[]"))
+	}
+	if len(value) > 253 {
+		return fmt.Errorf("host %q is longer than 253 characters", value)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(value, "."), ".") {
+		if !hostnameLabel.MatchString(label) {
+			return fmt.Errorf("%q is not a valid hostname or IP address", value)
+		}
+	}
+	return nil
+}
+
+// Port validates a TCP port in the range 1-65535
+func Port(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("port %q is not a number", value)
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d is out of range 1-65535", port)
+	}
+	return nil
+}
+
+// PortAvailable checks that nothing listens on the TCP port on this machine
+func PortAvailable(value string) error {
+	if err := Port(value); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort("", value))
+	if err != nil {
+		return fmt.Errorf("port %s is already in use on this machine", value)
+	}
+	ln.Close()
+	return nil
+}
+
+// RedisDB returns a validator for a Redis database index below databases,
+// the server's configured number of databases (16 by default)
+func RedisDB(databases int) func(string) error {
+	return func(value string) error {
+		index, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("Redis database %q is not a number", value)
+		}
+		if index < 0 || index >= databases {
+			return fmt.Errorf("Redis database %d is out of range 0-%d", index, databases-1)
+		}
+		return nil
+	}
+}
+
+// Identifier returns a validator for unquoted SQL identifiers of the given driver
+func Identifier(driver string) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return fmt.Errorf("identifier cannot be empty")
+		}
+
+		switch driver {
+		case "pgsql":
+			if len(value) > 63 {
+				return fmt.Errorf("%q is longer than 63 characters", value)
+			}
+			if !pgsqlIdent.MatchString(value) {
+				return fmt.Errorf("%q must start with a letter or underscore and contain only letters, digits, _ or $", value)
+			}
+		default:
+			if len(value) > 64 {
+				return fmt.Errorf("%q is longer than 64 characters", value)
+			}
+			if !mysqlIdent.MatchString(value) {
+				return fmt.Errorf("%q may contain only letters, digits, _ or $", value)
+			}
+			if allDigits.MatchString(value) {
+				return fmt.Errorf("%q cannot consist solely of digits", value)
+			}
+		}
+		return nil
+	}
+}
+
+// Email validates a plain email address
+func Email(value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return fmt.Errorf("%q is not a valid email address", value)
+	}
+	return nil
+}
+
+// NotEmpty rejects empty values
+func NotEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("value cannot be empty")
+	}
+	return nil
+}
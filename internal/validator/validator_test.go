@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) error
+		valid    []string
+		invalid  []string
+	}{
+		{"Driver", Driver, []string{"mysql", "pgsql"}, []string{"", "sqlite", "MySQL"}},
+		{"Host", Host, []string{"127.0.0.1", "::1", "localhost", "db.example.com", "example.com."}, []string{"", "[::1]", "-db", "db_1", "a..b", strings.Repeat("a", 64) + ".com"}},
+		{"Port", Port, []string{"1", "3306", "65535"}, []string{"", "0", "65536", "-1", "http"}},
+		{"RedisDB", RedisDB(16), []string{"0", "15"}, []string{"16", "-1", "one"}},
+		{"Identifier mysql", Identifier("mysql"), []string{"mineadmin", "1st_db", "$db", strings.Repeat("a", 64)}, []string{"", "123", "mine-admin", "mine admin", strings.Repeat("a", 65)}},
+		{"Identifier pgsql", Identifier("pgsql"), []string{"mineadmin", "_db", "db$1", strings.Repeat("a", 63)}, []string{"", "1st_db", "$db", "mine-admin", strings.Repeat("a", 64)}},
+		{"Email", Email, []string{"admin@example.com"}, []string{"", "admin", "Admin <admin@example.com>"}},
+		{"NotEmpty", NotEmpty, []string{"a"}, []string{"", "  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range tt.valid {
+				if err := tt.validate(v); err != nil {
+					t.Errorf("%q rejected: %v", v, err)
+				}
+			}
+			for _, v := range tt.invalid {
+				if err := tt.validate(v); err == nil {
+					t.Errorf("%q accepted", v)
+				}
+			}
+		})
+	}
+}
+
+func TestPortAvailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	defer ln.Close()
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+
+	if err := PortAvailable(port); err == nil {
+		t.Errorf("port %s is in use but accepted", port)
+	}
+	if err := PortAvailable("http"); err == nil {
+		t.Error("invalid port accepted")
+	}
+}